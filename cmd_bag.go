@@ -0,0 +1,69 @@
+package main
+
+import (
+	"aoc2023/day02"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+)
+
+// bagCommand infers the bag contents from the games of day 2, the minimal bag all the selected
+// games are possible with, and lists the selected games that rule out the given bag
+func bagCommand(args []string) error {
+	flags := flag.NewFlagSet("bag", flag.ExitOnError)
+	variant := flags.String("input", settings.Input, "input variant, e.g. input_test (default: the input of the day)")
+	bagString := flags.String("bag", day02.DEFAULT_BAG.String(), "the bag to check the games against, any colors")
+	flags.Parse(args)
+
+	day, _ := parseDay("2")
+	if *variant == "" {
+		*variant = day.DefaultVariant()
+	}
+
+	bag, err := day02.ParseCubeSet(*bagString)
+	if err != nil {
+		return fmt.Errorf("invalid bag: %w", err)
+	}
+
+	fd, err := os.Open(day.VariantPath(*variant))
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	games, err := day02.Parse(fd)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		selectedIds := []int{}
+		for _, arg := range flags.Args() {
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid game ID %q", arg)
+			}
+			selectedIds = append(selectedIds, id)
+		}
+		games = slices.DeleteFunc(games, func(game *day02.Game) bool {
+			return !slices.Contains(selectedIds, game.Id)
+		})
+	}
+
+	minimalBag := day02.MinimalBag(games)
+	fmt.Printf("%d games, minimal bag: %s\n", len(games), minimalBag)
+
+	impossibleGames := day02.RulingOut(games, bag)
+	if len(impossibleGames) == 0 {
+		fmt.Printf("all the games are possible with %s\n", bag)
+		return nil
+	}
+
+	fmt.Printf("%d games rule out %s:\n", len(impossibleGames), bag)
+	for _, game := range impossibleGames {
+		fmt.Printf("  Game %d needs %s\n", game.Id, day02.MinGameCubeSet(game))
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"aoc2023/parallel"
+	"aoc2023/runner"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// benchCommand solves the parts of a day repeatedly and reports the spread of the durations
+// and the allocations of a single run, the solver output is discarded. With -speedup the parts
+// run again on a single worker and the median durations are compared.
+func benchCommand(args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	variant := flags.String("input", settings.Input, "input variant, e.g. input_test (default: the input of the day)")
+	part := flags.Int("part", 0, "benchmark only the given part")
+	count := flags.Int("count", 10, "number of runs of every part")
+	speedup := flags.Bool("speedup", false, "also run the parts on a single worker and report the speedup of the workers")
+	flags.Parse(args)
+
+	day, err := parseDay(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *variant == "" {
+		*variant = day.DefaultVariant()
+	}
+	if *count < 1 {
+		return fmt.Errorf("the count must be at least 1, got %d", *count)
+	}
+
+	lines, err := runner.ReadInput(day.VariantPath(*variant))
+	if err != nil {
+		return err
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	columns := []string{"PART", "ANSWER", "MIN", "MEDIAN", "MEAN", "MAX", "ALLOCS/RUN", "BYTES/RUN"}
+	if *speedup {
+		columns = append(columns, "SPEEDUP")
+	}
+	fmt.Printf("%s, input: %s, runs: %d, workers: %d\n", day, *variant, *count, parallel.Workers)
+	fmt.Fprintln(table, strings.Join(columns, "\t"))
+
+	for p := 1; p <= len(day.Parts); p++ {
+		if *part != 0 && *part != p {
+			continue
+		}
+
+		durations, result, err := benchPart(day, p, lines, *count)
+		if err != nil {
+			return err
+		}
+
+		var total time.Duration
+		for _, duration := range durations {
+			total += duration
+		}
+		median := durations[len(durations)/2]
+
+		fmt.Fprintf(table, "%d\t%d\t%v\t%v\t%v\t%v\t%d\t%d", p, result.Answer,
+			durations[0], median, total/time.Duration(*count), durations[len(durations)-1],
+			result.Allocations, result.AllocatedBytes)
+
+		if *speedup {
+			parallel.WithWorkers(1, func() {
+				var sequentialDurations []time.Duration
+				sequentialDurations, _, err = benchPart(day, p, lines, *count)
+				if err == nil {
+					sequentialMedian := sequentialDurations[len(sequentialDurations)/2]
+					fmt.Fprintf(table, "\t%.2fx", float64(sequentialMedian)/float64(max(median, 1)))
+				}
+			})
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Fprintln(table)
+	}
+
+	return table.Flush()
+}
+
+// benchPart solves the part count times and returns the sorted durations and the last result
+func benchPart(day runner.Day, part int, lines []string, count int) ([]time.Duration, runner.Result, error) {
+	durations := make([]time.Duration, count)
+	var result runner.Result
+	for i := range durations {
+		result = day.Solve(part, lines, io.Discard)
+		if result.Err != nil {
+			return nil, result, fmt.Errorf("part %d: %w", part, result.Err)
+		}
+		durations[i] = result.Duration
+	}
+	slices.Sort(durations)
+	return durations, result, nil
+}
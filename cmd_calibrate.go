@@ -0,0 +1,64 @@
+package main
+
+import (
+	"aoc2023/day01"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// calibrateCommand runs the calibration extractor of day 1 on any text, with a built-in
+// dictionary or a token file, and prints the total and optionally what was matched on every line
+func calibrateCommand(args []string) error {
+	flags := flag.NewFlagSet("calibrate", flag.ExitOnError)
+	dictionaryName := flags.String("dictionary", "english", "built-in dictionary ("+strings.Join(dictionaryNames(), ", ")+") or a token file")
+	report := flags.Bool("report", false, "print the tokens matched on every line")
+	policyName := flags.String("policy", "error", "what to do with a line without a digit: "+strings.Join(day01.POLICY_NAMES, ", "))
+	flags.Parse(args)
+
+	policy, err := day01.ParsePolicy(*policyName)
+	if err != nil {
+		return err
+	}
+
+	dictionary, err := day01.LoadDictionary(*dictionaryName)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader = os.Stdin
+	if flags.NArg() > 0 {
+		fd, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer fd.Close()
+		reader = fd
+	}
+
+	// the document is streamed, it may be larger than the memory
+	var printReport func(day01.LineReport)
+	if *report {
+		printReport = func(lineReport day01.LineReport) { fmt.Println(lineReport) }
+	}
+
+	calibration, err := day01.Calibrate(reader, day01.NewMatcher(dictionary), policy, printReport)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("total calibration value: %d (%d lines calibrated, %d skipped)\n", calibration.Total, calibration.Lines, calibration.Skipped)
+	return nil
+}
+
+func dictionaryNames() []string {
+	names := []string{}
+	for name := range day01.DICTIONARIES {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"aoc2023/runner"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// compareCommand runs the day against every named input in <dir>/dayNN/*.txt,
+// e.g. inputs/day05/alice.txt, and checks them with <dir>/dayNN/answers.json
+func compareCommand(args []string) error {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	dir := flags.String("dir", settings.InputsDir, "directory with a dayNN directory of named inputs for every day")
+	budget := flags.Duration("budget", time.Duration(settings.Timeout), "time budget of a single part")
+	flags.Parse(args)
+
+	day, err := parseDay(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	inputsDir := filepath.Join(*dir, day.Dir())
+	paths, err := filepath.Glob(filepath.Join(inputsDir, "*.txt"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no inputs found in %s", inputsDir)
+	}
+	sort.Strings(paths)
+
+	answers, err := runner.ReadAnswers(filepath.Join(inputsDir, runner.ANSWERS_FILE_NAME))
+	if err != nil {
+		return err
+	}
+
+	// keep the matrix readable, whatever the solvers print goes to stderr
+	stdout, restore := runner.RedirectStdout(os.Stderr)
+	defer restore()
+
+	table := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	header := []string{"INPUT"}
+	for part := 1; part <= len(day.Parts); part++ {
+		header = append(header, fmt.Sprintf("PART %d", part), "TIME", "STATUS")
+	}
+
+	fmt.Fprintf(stdout, "%s, time budget: %v\n", day, *budget)
+	fmt.Fprintln(table, strings.Join(header, "\t"))
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".txt")
+		row := []string{name}
+
+		lines, err := runner.ReadInput(path)
+		if err != nil {
+			return err
+		}
+
+		for part := 1; part <= len(day.Parts); part++ {
+			result := day.SolveWithBudget(part, lines, *budget)
+			answer := fmt.Sprint(result.Answer)
+			if result.Err != nil {
+				answer = "-"
+			}
+
+			row = append(row, answer, result.Duration.Round(time.Microsecond).String(), answers.Verdict(name, result))
+		}
+
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+
+	return table.Flush()
+}
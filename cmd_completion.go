@@ -0,0 +1,120 @@
+package main
+
+import (
+	"aoc2023/runner"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const BASH_COMPLETION = `# bash completion of {{.Program}}, load it with: source <({{.Program}} completion bash)
+_{{.Function}}() {
+	local current previous command
+	current="${COMP_WORDS[COMP_CWORD]}"
+	previous="${COMP_WORDS[COMP_CWORD-1]}"
+	command="${COMP_WORDS[1]}"
+
+	case "$previous" in
+	-input) COMPREPLY=($(compgen -W "{{.Variants}}" -- "$current")); return ;;
+	esac
+
+	if [ "$COMP_CWORD" -eq 1 ]; then
+		COMPREPLY=($(compgen -W "{{.Commands}} {{.Days}}" -- "$current"))
+		return
+	fi
+
+	case "$command" in
+	completion) COMPREPLY=($(compgen -W "{{.Shells}}" -- "$current")) ;;
+	config) COMPREPLY=($(compgen -W "show" -- "$current")) ;;
+	*) COMPREPLY=($(compgen -W "{{.Days}}" -- "$current")) ;;
+	esac
+}
+complete -F _{{.Function}} {{.Program}}
+`
+
+const ZSH_COMPLETION = `#compdef {{.Program}}
+# zsh completion of {{.Program}}, load it with: source <({{.Program}} completion zsh)
+_{{.Function}}() {
+	if [[ "${words[CURRENT-1]}" == "-input" ]]; then
+		compadd -- {{.Variants}}
+		return
+	fi
+
+	if (( CURRENT == 2 )); then
+		compadd -- {{.Commands}} {{.Days}}
+		return
+	fi
+
+	case "${words[2]}" in
+	completion) compadd -- {{.Shells}} ;;
+	config) compadd -- show ;;
+	*) compadd -- {{.Days}} ;;
+	esac
+}
+compdef _{{.Function}} {{.Program}}
+`
+
+const FISH_COMPLETION = `# fish completion of {{.Program}}, load it with: {{.Program}} completion fish | source
+complete -c {{.Program}} -f
+complete -c {{.Program}} -n "__fish_use_subcommand" -a "{{.Commands}} {{.Days}}"
+complete -c {{.Program}} -n "__fish_seen_subcommand_from completion" -a "{{.Shells}}"
+complete -c {{.Program}} -n "__fish_seen_subcommand_from config" -a "show"
+complete -c {{.Program}} -n "not __fish_use_subcommand; and not __fish_seen_subcommand_from completion config" -a "{{.Days}}"
+complete -c {{.Program}} -o input -r -a "{{.Variants}}"
+`
+
+var COMPLETIONS = map[string]string{
+	"bash": BASH_COMPLETION,
+	"zsh":  ZSH_COMPLETION,
+	"fish": FISH_COMPLETION,
+}
+
+// the completion lists the commands, so it cannot be a part of their initializer
+func init() {
+	commands = append(commands, Command{"completion", "completion bash|zsh|fish", completionCommand})
+}
+
+// completionCommand generates the completion script of a shell, the registered days
+// and the input variants found at the time of generating it are baked in
+func completionCommand(args []string) error {
+	shells := []string{}
+	for shell := range COMPLETIONS {
+		shells = append(shells, shell)
+	}
+	slices.Sort(shells)
+
+	if len(args) != 1 || COMPLETIONS[args[0]] == "" {
+		return fmt.Errorf("usage: completion %s", strings.Join(shells, "|"))
+	}
+
+	commandNames := []string{}
+	for _, command := range commands {
+		commandNames = append(commandNames, command.Name)
+	}
+
+	days := []string{}
+	variants := []string{}
+	for _, day := range runner.Days {
+		days = append(days, strconv.Itoa(day.Number))
+		variants = append(variants, day.Variants()...)
+	}
+	slices.Sort(variants)
+	variants = slices.Compact(variants)
+
+	program := filepath.Base(os.Args[0])
+	function := strings.NewReplacer("-", "_", ".", "_").Replace(program)
+
+	completion := template.Must(template.New(args[0]).Parse(COMPLETIONS[args[0]]))
+	return completion.Execute(os.Stdout, map[string]string{
+		"Program":  program,
+		"Function": function,
+		"Commands": strings.Join(commandNames, " "),
+		"Days":     strings.Join(days, " "),
+		"Variants": strings.Join(variants, " "),
+		"Shells":   strings.Join(shells, " "),
+	})
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"aoc2023/day05"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// convertCommand converts numbers or ranges of day 5 between any two categories of the almanac,
+// or back with -inverse, e.g. the seeds of a location, a range is written as start+length, e.g. 79+14
+func convertCommand(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	variant := flags.String("input", settings.Input, "input variant, e.g. input_test (default: the input of the day)")
+	fromCategory := flags.String("from", day05.SEED_CATEGORY, "category of the numbers")
+	toCategory := flags.String("to", day05.LOCATION_CATEGORY, "category to convert the numbers to")
+	inverse := flags.Bool("inverse", false, "convert the numbers of the to category back to the from category")
+	check := flags.Bool("check", false, "check that the inverse converts forward to the numbers again")
+	flags.Parse(args)
+
+	day, _ := parseDay("5")
+	if *variant == "" {
+		*variant = day.DefaultVariant()
+	}
+
+	fd, err := os.Open(day.VariantPath(*variant))
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	almanac, err := day05.Parse(fd)
+	if err != nil {
+		return err
+	}
+
+	route, err := almanac.Route(*fromCategory, *toCategory)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("route: %s\n", route)
+
+	for _, arg := range flags.Args() {
+		ranges, err := parseRanges(arg)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case *inverse && *check:
+			if err := route.CheckInverse(ranges); err != nil {
+				return err
+			}
+			fmt.Printf("%s <- %s\tOK\n", arg, rangesString(route.InverseRanges(ranges)))
+		case *inverse:
+			fmt.Printf("%s <- %s\n", arg, rangesString(route.InverseRanges(ranges)))
+		case strings.Contains(arg, "+"):
+			fmt.Printf("%s -> %s\n", arg, rangesString(route.ConvertRanges(ranges)))
+		default:
+			fmt.Printf("%s -> %d\n", arg, route.ConvertInt(ranges[0].Start))
+		}
+	}
+	return nil
+}
+
+// parseRanges parses a number, as a range of length 1, or a range written as start+length
+func parseRanges(arg string) ([]day05.Range, error) {
+	startString, lengthString, isRange := strings.Cut(arg, "+")
+	if !isRange {
+		lengthString = "1"
+	}
+
+	start, startErr := strconv.Atoi(startString)
+	length, lengthErr := strconv.Atoi(lengthString)
+	if startErr != nil || lengthErr != nil || length < 1 {
+		return nil, fmt.Errorf("invalid number or range %q, expected number or start+length", arg)
+	}
+	return []day05.Range{{Start: start, Length: length}}, nil
+}
+
+func rangesString(ranges []day05.Range) string {
+	rangeStrings := make([]string, len(ranges))
+	for i, range_ := range ranges {
+		rangeStrings[i] = fmt.Sprintf("%d+%d", range_.Start, range_.Length)
+	}
+	return strings.Join(rangeStrings, " ")
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"aoc2023/runner"
+	"aoc2023/trace"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// explainCommand traces how a day handles a single item of its input, e.g.
+// a hand of day 7, and prints the steps or exports them as JSON
+func explainCommand(args []string) error {
+	flags := flag.NewFlagSet("explain", flag.ExitOnError)
+	variant := flags.String("input", settings.Input, "input variant, e.g. input_test (default: the input of the day)")
+	asJSON := flags.Bool("json", false, "print the trace as JSON")
+	flags.Parse(args)
+
+	day, err := parseDay(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *variant == "" {
+		*variant = day.DefaultVariant()
+	}
+
+	explain, ok := runner.Explainers[day.Number]
+	if !ok {
+		return fmt.Errorf("%s cannot be explained, try days %v", day, explainedDays())
+	}
+
+	item, err := strconv.Atoi(flags.Arg(1))
+	if err != nil {
+		return fmt.Errorf("Please provide the item to explain, e.g. the line of the hand")
+	}
+
+	lines, err := runner.ReadInput(day.VariantPath(*variant))
+	if err != nil {
+		return err
+	}
+
+	tracer := &trace.Tracer{}
+	if err := explain(lines, item, tracer); err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return json.NewEncoder(os.Stdout).Encode(tracer.Events)
+	}
+
+	fmt.Printf("%s, input: %s, item: %d\n", day, *variant, item)
+	return trace.Write(os.Stdout, tracer.Events)
+}
+
+func explainedDays() []int {
+	days := []int{}
+	for day := range runner.Explainers {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const INPUT_URL = "https://adventofcode.com/2023/day/%d/input"
+const USER_AGENT = "github.com/mattrym/aoc2023"
+
+// fetchCommand downloads the puzzle input of a day with the session token of the
+// user, e.g. the value of the session cookie of adventofcode.com
+func fetchCommand(args []string) error {
+	flags := flag.NewFlagSet("fetch", flag.ExitOnError)
+	sessionFile := flags.String("session", settings.SessionFile, "file with the session token")
+	force := flags.Bool("force", false, "overwrite an existing input")
+	flags.Parse(args)
+
+	dayNumber, err := strconv.Atoi(flags.Arg(0))
+	if err != nil || dayNumber < 1 || dayNumber > 25 {
+		return fmt.Errorf("Please provide a day number between 1 and 25")
+	}
+
+	// the day does not need to be registered yet, e.g. right after new
+	path := filepath.Join(fmt.Sprintf("day%02d", dayNumber), "input.txt")
+	if _, err := os.Stat(path); err == nil && !*force {
+		return fmt.Errorf("%s already exists, use -force to overwrite it", path)
+	}
+
+	token, err := os.ReadFile(*sessionFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no session token in %s, copy the session cookie of adventofcode.com there", *sessionFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf(INPUT_URL, dayNumber), nil)
+	if err != nil {
+		return err
+	}
+	request.AddCookie(&http.Cookie{Name: "session", Value: strings.TrimSpace(string(token))})
+	request.Header.Set("User-Agent", USER_AGENT)
+
+	client := http.Client{Timeout: 30 * time.Second}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching the input of day %d: %s", dayNumber, response.Status)
+	}
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %d bytes to %s\n", len(content), path)
+	return nil
+}
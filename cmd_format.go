@@ -0,0 +1,74 @@
+package main
+
+import (
+	"aoc2023/runner"
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// formatCommand writes the input of a day in the canonical form, with -check
+// it verifies that formatting the parsed input and parsing it back is stable
+func formatCommand(args []string) error {
+	flags := flag.NewFlagSet("format", flag.ExitOnError)
+	variant := flags.String("input", settings.Input, "input variant, e.g. input_test (default: the input of the day)")
+	check := flags.Bool("check", false, "check the round trip instead of printing the canonical input")
+	flags.Parse(args)
+
+	day, err := parseDay(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *variant == "" {
+		*variant = day.DefaultVariant()
+	}
+
+	original, err := os.ReadFile(day.VariantPath(*variant))
+	if err != nil {
+		return err
+	}
+
+	// the parsers of some days print diagnostics, keep them out of the canonical text
+	stdout, restore := runner.RedirectStdout(os.Stderr)
+	defer restore()
+
+	var canonical bytes.Buffer
+	if err := day.Reformat(bytes.NewReader(original), &canonical); err != nil {
+		return fmt.Errorf("parse %s: %w", *variant, err)
+	}
+
+	if !*check {
+		_, err := stdout.Write(canonical.Bytes())
+		return err
+	}
+
+	var reformatted bytes.Buffer
+	if err := day.Reformat(bytes.NewReader(canonical.Bytes()), &reformatted); err != nil {
+		return fmt.Errorf("parse canonical %s: %w", *variant, err)
+	}
+
+	if line, differs := firstDifference(canonical.String(), reformatted.String()); differs {
+		return fmt.Errorf("%s, input: %s: round trip changes line %d", day, *variant, line)
+	}
+
+	fmt.Fprintf(stdout, "%s, input: %s: round trip OK", day, *variant)
+	if line, differs := firstDifference(string(original), canonical.String()); differs {
+		fmt.Fprintf(stdout, ", the input is not canonical from line %d", line)
+	}
+	fmt.Fprintln(stdout)
+	return nil
+}
+
+func firstDifference(text1, text2 string) (int, bool) {
+	lines1 := strings.Split(strings.TrimRight(text1, "\n"), "\n")
+	lines2 := strings.Split(strings.TrimRight(text2, "\n"), "\n")
+
+	for i := 0; i < max(len(lines1), len(lines2)); i++ {
+		if i >= len(lines1) || i >= len(lines2) || lines1[i] != lines2[i] {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"aoc2023/history"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// historyCommand shows how the answers, timings and allocations of the parts
+// changed between the commits and flags the regressions beyond the threshold
+func historyCommand(args []string) error {
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	path := flags.String("file", settings.History, "history file written by the run command")
+	threshold := flags.Float64("threshold", 0.2, "relative slowdown or allocation growth reported as a regression, 0.2 is 20%")
+	last := flags.Int("last", 10, "number of the most recent commits to show per part, 0 or less shows all of them")
+	flags.Parse(args)
+
+	dayNumber := 0
+	if flags.NArg() > 0 {
+		day, err := parseDay(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		dayNumber = day.Number
+	}
+
+	records, err := history.Read(*path)
+	if err != nil {
+		return err
+	}
+
+	regressions := 0
+	table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+
+	for _, trend := range history.Trends(records, *threshold) {
+		if dayNumber != 0 && trend.Day != dayNumber {
+			continue
+		}
+
+		fmt.Fprintf(table, "\nDay %02d [PART %d], input: %s\n", trend.Day, trend.Part, trend.Variant)
+		fmt.Fprintln(table, "COMMIT\tDATE\tRUNS\tBEST TIME\tCHANGE\tALLOCATIONS\tCHANGE\tANSWER\t")
+
+		points := trend.Points
+		if *last > 0 && len(points) > *last {
+			points = points[len(points)-*last:]
+		}
+		for _, point := range points {
+			answer := strconv.Itoa(point.Answer)
+			if point.AnswerChanged {
+				answer += " (CHANGED)"
+			}
+
+			marker := ""
+			if point.Regression {
+				marker = "REGRESSION"
+			}
+
+			fmt.Fprintf(table, "%s\t%s\t%d\t%v\t%s\t%d\t%s\t%s\t%s\n",
+				point.Commit, point.Time.Format(time.DateTime), point.Runs,
+				point.Duration.Round(time.Microsecond), percentage(point.DurationChange),
+				point.Allocations, percentage(point.AllocationsChange), answer, marker)
+		}
+
+		if trend.Latest().Regression {
+			regressions++
+		}
+	}
+
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if regressions > 0 {
+		return fmt.Errorf("%d parts regressed beyond %.0f%% at their latest commit", regressions, *threshold*100)
+	}
+	return nil
+}
+
+func percentage(change float64) string {
+	if change == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", change*100)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/template"
+)
+
+const DAY_TEMPLATE = `package day{{.Padded}}
+
+import (
+	"aoc2023/input"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const TITLE = {{printf "%q" .Title}}
+const INPUT_FILE_PATH = "day{{.Padded}}/input.txt"
+
+//go:embed samples
+var Samples embed.FS
+
+func Run() {
+	inputLines := readLines(INPUT_FILE_PATH)
+
+	answer, err := Part1(inputLines)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Answer [PART 1]: ", answer)
+
+	answer, err = Part2(inputLines)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Answer [PART 2]: ", answer)
+}
+
+func Part1(inputLines []string) (int, error) {
+	return 0, nil
+}
+
+func Part2(inputLines []string) (int, error) {
+	return 0, nil
+}
+
+func Parse(reader io.Reader) ([]string, error) {
+	return input.Lines(reader)
+}
+
+func Format(writer io.Writer, lines []string) error {
+	_, err := fmt.Fprintln(writer, strings.Join(lines, "\n"))
+	return err
+}
+
+func readLines(path string) []string {
+	fd, err := os.Open(path)
+	if err != nil {
+		panic(err)
+	}
+	defer fd.Close()
+
+	lines, err := input.Lines(fd)
+	if err != nil {
+		panic(err)
+	}
+	return lines
+}
+`
+
+// newCommand scaffolds the package of a new day with an empty example,
+// registering it is left to the author as the registry is plain Go code
+func newCommand(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ExitOnError)
+	title := flags.String("title", "", "title of the puzzle")
+	flags.Parse(args)
+
+	dayNumber, err := strconv.Atoi(flags.Arg(0))
+	if err != nil || dayNumber < 1 || dayNumber > 25 {
+		return fmt.Errorf("Please provide a day number between 1 and 25")
+	}
+
+	padded := fmt.Sprintf("%02d", dayNumber)
+	dir := "day" + padded
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "samples"), 0755); err != nil {
+		return err
+	}
+
+	fd, err := os.Create(filepath.Join(dir, "run.go"))
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	dayTemplate := template.Must(template.New("day").Parse(DAY_TEMPLATE))
+	if err := dayTemplate.Execute(fd, map[string]string{"Padded": padded, "Title": *title}); err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		filepath.Join(dir, "samples", "example.txt"):  "",
+		filepath.Join(dir, "samples", "answers.json"): "{\n  \"example\": {}\n}\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("created %s, register it in runner/registry.go:\n\n", dir)
+	fmt.Printf("\t{%d, %s.TITLE, %s.INPUT_FILE_PATH, %s.Run, []Solver{%s.Part1, %s.Part2}, reformat(%s.Parse, %s.Format), %s.Samples},\n",
+		dayNumber, dir, dir, dir, dir, dir, dir, dir, dir)
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"aoc2023/history"
+	"aoc2023/runner"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runCommand runs the parts of a day through the registry and compares
+// the answers with the recorded ones, the solver output goes to stderr
+func runCommand(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	variant := flags.String("input", settings.Input, "input variant, e.g. input_test (default: the input of the day)")
+	part := flags.Int("part", 0, "run only the given part")
+	asJSON := flags.Bool("json", settings.Format == "json", "print the results as JSON")
+	historyPath := flags.String("history", settings.History, "file to record the run to, empty to not record it")
+	sample := flags.Bool("sample", false, "run the embedded examples instead, -input picks a single one")
+	flags.Parse(args)
+
+	day, err := parseDay(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *sample {
+		names := day.SampleNames()
+		if isFlagSet(flags, "input") {
+			names = []string{*variant}
+		}
+		return runSamples(day, names, *part, *asJSON)
+	}
+	if *variant == "" {
+		*variant = day.DefaultVariant()
+	}
+
+	results, err := solveDay(day, *variant, *part)
+	if err != nil {
+		return err
+	}
+
+	if *historyPath != "" {
+		if err := recordHistory(*historyPath, *variant, results); err != nil {
+			fmt.Fprintln(os.Stderr, "history:", err)
+		}
+	}
+
+	if *asJSON {
+		return json.NewEncoder(os.Stdout).Encode(results)
+	}
+
+	answers, err := day.Answers()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s, input: %s\n", day, *variant)
+	for _, result := range results {
+		fmt.Printf("[PART %d] %s\t%s\n", result.Part, result, answers.Verdict(*variant, result))
+	}
+	return nil
+}
+
+// SampleResults are the results of a single embedded example
+type SampleResults struct {
+	Sample  string          `json:"sample"`
+	Results []runner.Result `json:"results"`
+}
+
+// runSamples runs the embedded examples of a day for the parts with a known answer and judges
+// them against the answers from the puzzle description, the runs are not recorded to the history
+func runSamples(day runner.Day, names []string, part int, asJSON bool) error {
+	if len(names) == 0 {
+		return fmt.Errorf("%s has no samples", day)
+	}
+
+	answers, err := day.SampleAnswers()
+	if err != nil {
+		return err
+	}
+
+	samples := []SampleResults{}
+	for _, name := range names {
+		lines, err := day.ReadSample(name)
+		if err != nil {
+			return err
+		}
+
+		// an example usually belongs to one part only, the other part may not even terminate on it
+		results := []runner.Result{}
+		for p := 1; p <= len(day.Parts); p++ {
+			if _, known := answers[name][p]; known && (part == 0 || part == p) {
+				results = append(results, day.Solve(p, lines, os.Stderr))
+			}
+		}
+		samples = append(samples, SampleResults{name, results})
+	}
+
+	if asJSON {
+		return json.NewEncoder(os.Stdout).Encode(samples)
+	}
+
+	for _, sample := range samples {
+		fmt.Printf("%s, sample: %s\n", day, sample.Sample)
+		for _, result := range sample.Results {
+			fmt.Printf("[PART %d] %s\t%s\n", result.Part, result, answers.Verdict(sample.Sample, result))
+		}
+	}
+	return nil
+}
+
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
+
+func recordHistory(path string, variant string, results []runner.Result) error {
+	commit := history.Commit()
+	records := make([]history.Record, len(results))
+	for i, result := range results {
+		records[i] = history.NewRecord(commit, variant, result)
+	}
+	return history.Append(path, records...)
+}
+
+func solveDay(day runner.Day, variant string, part int) ([]runner.Result, error) {
+	lines, err := runner.ReadInput(day.VariantPath(variant))
+	if err != nil {
+		return nil, err
+	}
+	return solveLines(day, lines, part), nil
+}
+
+func solveLines(day runner.Day, lines []string, part int) []runner.Result {
+	results := []runner.Result{}
+	for p := 1; p <= len(day.Parts); p++ {
+		if part == 0 || part == p {
+			results = append(results, day.Solve(p, lines, os.Stderr))
+		}
+	}
+	return results
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"aoc2023/day03"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// schematicCommand sums up the gears of day 3 by a custom rule, or exports the adjacency
+// of the numbers and the symbols of the schematic as JSON or Graphviz DOT
+func schematicCommand(args []string) error {
+	flags := flag.NewFlagSet("schematic", flag.ExitOnError)
+	variant := flags.String("input", settings.Input, "input variant, e.g. input_test (default: the input of the day)")
+	ruleString := flags.String("gear", day03.DEFAULT_GEAR_RULE.String(), "gear rule: <symbol>=<count>:<aggregate> or <symbol>>=<count>:<aggregate>, aggregates: product, sum, max")
+	graphFormat := flags.String("graph", "", "export the adjacency graph instead: json or dot")
+	flags.Parse(args)
+
+	day, _ := parseDay("3")
+	if *variant == "" {
+		*variant = day.DefaultVariant()
+	}
+
+	rule, err := day03.ParseGearRule(*ruleString)
+	if err != nil {
+		return err
+	}
+
+	fd, err := os.Open(day.VariantPath(*variant))
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	bytemap, err := day03.Parse(fd)
+	if err != nil {
+		return err
+	}
+
+	switch *graphFormat {
+	case "":
+		fmt.Printf("%s, input: %s, sum of gears %s: %d\n", day, *variant, rule, day03.SumOfGears(bytemap, rule))
+		return nil
+	case "json":
+		return day03.BuildGraph(bytemap).WriteJSON(os.Stdout)
+	case "dot":
+		return day03.BuildGraph(bytemap).WriteDOT(os.Stdout)
+	default:
+		return fmt.Errorf("invalid graph format %q, expected json or dot", *graphFormat)
+	}
+}
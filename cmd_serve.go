@@ -0,0 +1,34 @@
+package main
+
+import (
+	"aoc2023/runner"
+	"aoc2023/server"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// serveCommand starts the HTTP API of the solvers
+func serveCommand(args []string) error {
+	api, addr := newAPI(args)
+
+	fmt.Fprintf(os.Stderr, "Listening on http://%s\n", addr)
+	return http.ListenAndServe(addr, api)
+}
+
+// newAPI builds the handler of the serve command from its flags, it returns the address to listen on
+func newAPI(args []string) (*server.Server, string) {
+	api := server.NewServer(runner.Days)
+	api.Timeout = time.Duration(settings.Timeout)
+
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8023", "address to listen on")
+	flags.Int64Var(&api.MaxBodySize, "max-body", api.MaxBodySize, "maximum size of an input, in bytes")
+	flags.DurationVar(&api.Timeout, "timeout", api.Timeout, "time budget of a single request")
+	flags.IntVar(&api.MaxConcurrent, "concurrency", api.MaxConcurrent, "maximum number of solvers running at once")
+	flags.Parse(args)
+
+	return api, *addr
+}
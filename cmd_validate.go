@@ -0,0 +1,57 @@
+package main
+
+import (
+	"aoc2023/runner"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// validateCommand checks the inputs of the given days, or of all the days, and
+// reports all the problems at once instead of letting a solver panic on them
+func validateCommand(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	variant := flags.String("input", settings.Input, "input variant, e.g. input_test (default: the input of the day)")
+	flags.Parse(args)
+
+	days, err := parseDays(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	// the parsers of some days print diagnostics
+	stdout, restore := runner.RedirectStdout(os.Stderr)
+	defer restore()
+
+	invalid := 0
+	for _, day := range days {
+		dayVariant := *variant
+		if dayVariant == "" {
+			dayVariant = day.DefaultVariant()
+		}
+
+		lines, err := runner.ReadInput(day.VariantPath(dayVariant))
+		if err != nil {
+			fmt.Fprintf(stdout, "%s, input: %s: %v\n", day, dayVariant, err)
+			invalid++
+			continue
+		}
+
+		problems := day.Validate(lines)
+		if len(problems) == 0 {
+			fmt.Fprintf(stdout, "%s, input: %s: OK\n", day, dayVariant)
+			continue
+		}
+
+		invalid++
+		fmt.Fprintf(stdout, "%s, input: %s: INVALID\n", day, dayVariant)
+		for _, problem := range problems {
+			fmt.Fprintf(stdout, "  %v\n", problem)
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d inputs are invalid", invalid, len(days))
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"aoc2023/runner"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// verifyCommand checks the answers of the given days, or of all the days, against the
+// recorded answers of their inputs and the answers of the embedded examples
+func verifyCommand(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	variant := flags.String("input", settings.Input, "input variant, e.g. input_test (default: the input of the day)")
+	samples := flags.Bool("samples", true, "verify the embedded examples as well")
+	budget := flags.Duration("budget", time.Duration(settings.Timeout), "time budget of a single part")
+	flags.Parse(args)
+
+	days, err := parseDays(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	// whatever the solvers print goes to stderr
+	stdout, restore := runner.RedirectStdout(os.Stderr)
+	defer restore()
+
+	checks, failed := 0, 0
+	verify := func(day runner.Day, label string, name string, lines []string, answers runner.Answers) {
+		for part := 1; part <= len(day.Parts); part++ {
+			if _, known := answers.Expected(name, part); !known {
+				continue
+			}
+
+			result := day.SolveWithBudget(part, lines, *budget)
+			verdict := answers.Verdict(name, result)
+			if verdict != "OK" {
+				failed++
+			}
+			checks++
+			fmt.Fprintf(stdout, "%s, %s [PART %d] %s\t%s\n", day, label, part, result, verdict)
+		}
+	}
+
+	for _, day := range days {
+		dayVariant := *variant
+		if dayVariant == "" {
+			dayVariant = day.DefaultVariant()
+		}
+
+		answers, err := day.Answers()
+		if err != nil {
+			return err
+		}
+
+		lines, err := runner.ReadInput(day.VariantPath(dayVariant))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			fmt.Fprintf(stdout, "%s, input: %s: SKIPPED (no input)\n", day, dayVariant)
+		case err != nil:
+			return err
+		default:
+			verify(day, "input: "+dayVariant, dayVariant, lines, answers)
+		}
+
+		if !*samples {
+			continue
+		}
+
+		sampleAnswers, err := day.SampleAnswers()
+		if err != nil {
+			return err
+		}
+		for _, name := range day.SampleNames() {
+			lines, err := day.ReadSample(name)
+			if err != nil {
+				return err
+			}
+			verify(day, "sample: "+name, name, lines, sampleAnswers)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, checks)
+	}
+	fmt.Fprintf(stdout, "all %d checks passed\n", checks)
+	return nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// versionCommand prints the module version and the VCS state the binary was built from
+func versionCommand(args []string) error {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return fmt.Errorf("the binary has no build info")
+	}
+
+	fmt.Printf("%s %s\n", info.Main.Path, info.Main.Version)
+	fmt.Printf("%-14s %s\n", "go:", info.GoVersion)
+
+	buildSettings := map[string]string{}
+	for _, setting := range info.Settings {
+		buildSettings[setting.Key] = setting.Value
+	}
+
+	for _, key := range []string{"vcs.revision", "vcs.time", "vcs.modified", "GOOS", "GOARCH"} {
+		if value, ok := buildSettings[key]; ok {
+			fmt.Printf("%-14s %s\n", key+":", value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"aoc2023/runner"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// watchCommand polls the package directory of the day, including its inputs and
+// answers, and re-runs the day in a fresh build every time something changes
+func watchCommand(args []string) error {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	variant := flags.String("input", settings.Input, "input variant, e.g. input_test (default: the input of the day)")
+	interval := flags.Duration("interval", time.Second, "how often to check for changes")
+	flags.Parse(args)
+
+	day, err := parseDay(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *variant == "" {
+		*variant = day.DefaultVariant()
+	}
+
+	fmt.Printf("Watching %s for changes, input: %s\n", day.Dir(), *variant)
+	lastSignature := ""
+	previousResults := map[int]runner.Result{}
+
+	for {
+		signature, err := directorySignature(day.Dir())
+		if err != nil {
+			return err
+		}
+
+		if signature != lastSignature {
+			lastSignature = signature
+			fmt.Printf("\n[%s] %s changed, re-running\n", time.Now().Format(time.TimeOnly), day)
+
+			results, err := runInSubprocess(day, *variant)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			} else {
+				printDiff(day, *variant, previousResults, results)
+				for _, result := range results {
+					previousResults[result.Part] = result
+				}
+			}
+		}
+
+		time.Sleep(*interval)
+	}
+}
+
+// directorySignature sums up names, sizes and modification times of all files
+func directorySignature(dir string) (string, error) {
+	var signature strings.Builder
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&signature, "%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+
+	return signature.String(), err
+}
+
+func runInSubprocess(day runner.Day, variant string) ([]runner.Result, error) {
+	var stdout bytes.Buffer
+
+	command := exec.Command("go", "run", ".", "run", "-json", "-input", variant, strconv.Itoa(day.Number))
+	command.Stdout = &stdout
+	command.Stderr = os.Stderr
+
+	if err := command.Run(); err != nil {
+		return nil, fmt.Errorf("build or run failed: %w", err)
+	}
+
+	var results []runner.Result
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		return nil, fmt.Errorf("invalid results: %w", err)
+	}
+	return results, nil
+}
+
+func printDiff(day runner.Day, variant string, previousResults map[int]runner.Result, results []runner.Result) {
+	answers, err := day.Answers()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	for _, result := range results {
+		change := "new"
+		if previous, ok := previousResults[result.Part]; ok {
+			switch {
+			case previous.Err != nil || result.Err != nil:
+				change = "was " + previous.String()
+			case previous.Answer == result.Answer:
+				change = fmt.Sprintf("unchanged, was %v", previous.Duration)
+			default:
+				change = fmt.Sprintf("changed, was %d", previous.Answer)
+			}
+		}
+
+		fmt.Printf("[PART %d] %s\t(%s)\t%s\n", result.Part, result, change, answers.Verdict(variant, result))
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"aoc2023/config"
+	"aoc2023/runner"
+	"aoc2023/tui"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type Command struct {
+	Name  string
+	Usage string
+	Run   func(args []string) error
+}
+
+var commands = []Command{
+	{"run", "run [-input variant] [-part n] [-json] [-sample] <day>", runCommand},
+	{"verify", "verify [-input variant] [-samples=false] [-budget duration] [day...]", verifyCommand},
+	{"bench", "bench [-input variant] [-part n] [-count n] [-speedup] <day>", benchCommand},
+	{"samples", "samples [day...]", samplesCommand},
+	{"bag", "bag [-input variant] [-bag cubes] [game...]", bagCommand},
+	{"schematic", "schematic [-input variant] [-gear rule] [-graph json|dot]", schematicCommand},
+	{"convert", "convert [-input variant] [-from category] [-to category] [-inverse [-check]] [number|start+length...]", convertCommand},
+	{"calibrate", "calibrate [-dictionary name|path] [-report] [-policy error|skip|zero] [file]", calibrateCommand},
+	{"watch", "watch [-input variant] [-interval duration] <day>", watchCommand},
+	{"compare", "compare [-dir inputs] [-budget duration] <day>", compareCommand},
+	{"explain", "explain [-input variant] [-json] <day> <item>", explainCommand},
+	{"history", "history [-file path] [-threshold ratio] [-last n] [day]", historyCommand},
+	{"validate", "validate [-input variant] [day...]", validateCommand},
+	{"format", "format [-input variant] [-check] <day>", formatCommand},
+	{"serve", "serve [-addr host:port] [-max-body bytes] [-timeout duration] [-concurrency n]", serveCommand},
+	{"config", "config show", configCommand},
+	{"tui", "tui", tuiCommand},
+	{"new", "new [-title title] <day>", newCommand},
+	{"fetch", "fetch [-session path] [-force] <day>", fetchCommand},
+	{"version", "version", versionCommand},
+}
+
+// commands that run without valid settings, e.g. to find out the version with a broken config
+var SETTINGS_FREE_COMMANDS = []string{"version", "completion"}
+
+func findCommand(name string) (Command, bool) {
+	for _, command := range commands {
+		if command.Name == name {
+			return command, true
+		}
+	}
+	return Command{}, false
+}
+
+func parseDay(arg string) (runner.Day, error) {
+	dayNumber, err := strconv.Atoi(arg)
+	if err != nil {
+		return runner.Day{}, fmt.Errorf("Please provide a valid day number")
+	}
+
+	day, ok := runner.Find(dayNumber)
+	if !ok {
+		return runner.Day{}, fmt.Errorf("Please provide a valid day number")
+	}
+	return day, nil
+}
+
+// parseDays parses the day numbers, no arguments mean all the days
+func parseDays(args []string) ([]runner.Day, error) {
+	if len(args) == 0 {
+		return runner.Days, nil
+	}
+
+	days := []runner.Day{}
+	for _, arg := range args {
+		day, err := parseDay(arg)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func tuiCommand(args []string) error {
+	browser := tui.Browser{Days: runner.Days, Input: os.Stdin, Output: os.Stdout}
+	browser.Run()
+	return nil
+}
+
+func configCommand(args []string) error {
+	if len(args) != 1 || args[0] != "show" {
+		return fmt.Errorf("usage: config show")
+	}
+
+	fmt.Printf("project config: %s\nuser config:    %s\n\n", config.ProjectPath(), config.UserPath())
+	fmt.Print(settings.Show())
+	return nil
+}
+
+// samplesCommand lists the embedded examples of the days and the parts with known answers
+func samplesCommand(args []string) error {
+	days, err := parseDays(args)
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		answers, err := day.SampleAnswers()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(day)
+		for _, name := range day.SampleNames() {
+			parts := []string{}
+			for part := 1; part <= len(day.Parts); part++ {
+				if _, ok := answers[name][part]; ok {
+					parts = append(parts, strconv.Itoa(part))
+				}
+			}
+			fmt.Printf("  %-16s parts: %s\n", name, strings.Join(parts, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const FILE_NAME = "aoc.json"
+const ENV_PREFIX = "AOC_"
+
+// Settings are the defaults of the runner, a zero field is not set
+type Settings struct {
+	Workers     int      `json:"workers,omitempty"`
+	Color       string   `json:"color,omitempty"`
+	Input       string   `json:"input,omitempty"`
+	InputsDir   string   `json:"inputs_dir,omitempty"`
+	Timeout     Duration `json:"timeout,omitempty"`
+	Format      string   `json:"format,omitempty"`
+	SessionFile string   `json:"session_file,omitempty"`
+	History     string   `json:"history,omitempty"`
+}
+
+// Duration is written as a Go duration in the config files, e.g. "10s"
+type Duration time.Duration
+
+// Config is the effective settings together with where every setting comes from
+type Config struct {
+	Settings
+	Sources map[string]string
+}
+
+// ProjectPath is the config in the repository root, the runner is run from there
+func ProjectPath() string {
+	return FILE_NAME
+}
+
+// UserPath is the config of the user, it overrides the project config
+func UserPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "aoc2023", FILE_NAME)
+}
+
+// DefaultSessionFile keeps the session token next to the user config
+func DefaultSessionFile() string {
+	if userPath := UserPath(); userPath != "" {
+		return filepath.Join(filepath.Dir(userPath), "session")
+	}
+	return "session"
+}
+
+// Load layers the defaults, the project config, the user config and the AOC_*
+// environment variables, each one overriding the previous. Flags come last,
+// they are applied with Set by whoever parses them, who validates the result.
+// The config is returned even with an error, a broken layer is left out, so
+// commands not depending on the settings can still run.
+func Load(defaults Settings) (*Config, error) {
+	config := &Config{Sources: make(map[string]string)}
+	config.merge(defaults, "default")
+
+	var errs []error
+	for _, path := range []string{ProjectPath(), UserPath()} {
+		if path == "" {
+			continue
+		}
+
+		settings, err := readFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		config.merge(settings, path)
+	}
+
+	for _, name := range Names() {
+		if value, ok := os.LookupEnv(ENV_PREFIX + strings.ToUpper(name)); ok && value != "" {
+			if err := config.Set(name, value, "env "+ENV_PREFIX+strings.ToUpper(name)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return config, errors.Join(errs...)
+}
+
+func readFile(path string) (Settings, error) {
+	var settings Settings
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(content)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&settings); err != nil {
+		return settings, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// Names lists the settings by their names in the config files
+func Names() []string {
+	settingsType := reflect.TypeOf(Settings{})
+	names := make([]string, settingsType.NumField())
+	for i := range names {
+		names[i] = fieldName(settingsType.Field(i))
+	}
+	return names
+}
+
+func (config *Config) merge(settings Settings, source string) {
+	values := reflect.ValueOf(settings)
+	target := reflect.ValueOf(&config.Settings).Elem()
+
+	for i := 0; i < values.NumField(); i++ {
+		if !values.Field(i).IsZero() {
+			target.Field(i).Set(values.Field(i))
+			config.Sources[fieldName(values.Type().Field(i))] = source
+		}
+	}
+}
+
+// Set overrides a single setting given by its name with a value from text, e.g. a flag
+func (config *Config) Set(name string, value string, source string) error {
+	target := reflect.ValueOf(&config.Settings).Elem()
+
+	for i := 0; i < target.NumField(); i++ {
+		if fieldName(target.Type().Field(i)) != name {
+			continue
+		}
+
+		field := target.Field(i)
+		switch field.Interface().(type) {
+		case string:
+			field.SetString(value)
+		case int:
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s from %s: %s", name, source, value)
+			}
+			field.SetInt(int64(number))
+		case Duration:
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s from %s: %s", name, source, value)
+			}
+			field.Set(reflect.ValueOf(Duration(duration)))
+		}
+
+		config.Sources[name] = source
+		return nil
+	}
+
+	return fmt.Errorf("unknown setting %s from %s", name, source)
+}
+
+// Validate checks the settings once all the layers are applied
+func (config *Config) Validate() error {
+	if config.Workers < 1 {
+		return fmt.Errorf("invalid workers from %s: must be at least 1", config.Sources["workers"])
+	}
+	if config.Format != "text" && config.Format != "json" {
+		return fmt.Errorf("invalid format from %s: %q, use text or json", config.Sources["format"], config.Format)
+	}
+	return nil
+}
+
+// Show writes the effective settings, one per line, with the source of every setting
+func (config *Config) Show() string {
+	var output strings.Builder
+	values := reflect.ValueOf(config.Settings)
+
+	for i := 0; i < values.NumField(); i++ {
+		name := fieldName(values.Type().Field(i))
+		source := config.Sources[name]
+		if source == "" {
+			source = "not set"
+		}
+		fmt.Fprintf(&output, "%-13s %-40v (%s)\n", name, values.Field(i).Interface(), source)
+	}
+
+	return output.String()
+}
+
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
+
+func (duration Duration) String() string {
+	return time.Duration(duration).String()
+}
+
+func (duration Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(duration.String())
+}
+
+func (duration *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*duration = Duration(parsed)
+	return nil
+}
@@ -1,89 +1,366 @@
 package day01
 
 import (
+	"aoc2023/input"
 	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 )
 
-type ma map[string]int
+// Dictionary maps the tokens found in a line to their digits
+type Dictionary struct {
+	Tokens map[string]int
+	// all the overlapping tokens are read, e.g. "oneight" is 1 and 8 in english, otherwise
+	// a token starting inside an accepted one is skipped, e.g. "VIII" is only 8 in roman numerals
+	Overlapping bool
+}
 
+const TITLE = "Trebuchet?!"
 const INPUT_FILE_PATH = "day01/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 func Run() {
 	// Read input file (from input.txt)
 	inputLines := readLines(INPUT_FILE_PATH)
-	stringsToDigits := mapStringsToDigits()
-
-	totalCalibrationValuePart1 := 0
-	totalCalibrationValuePart2 := 0
 
-	for _, inputLine := range inputLines {
-		calibrationValuePart1 := FindCalibrationValuePart1(inputLine)
-		calibrationValuePart2 := FindCalibrationValuePart2(inputLine, stringsToDigits)
+	totalCalibrationValuePart1, err := Part1(inputLines)
+	if err != nil {
+		panic(err)
+	}
 
-		totalCalibrationValuePart1 += calibrationValuePart1
-		totalCalibrationValuePart2 += calibrationValuePart2
+	totalCalibrationValuePart2, err := Part2(inputLines)
+	if err != nil {
+		panic(err)
 	}
 
 	// Print result
-	println("Total calibration value [PART 1]: ", totalCalibrationValuePart1)
-	println("Total calibration value [PART 2]: ", totalCalibrationValuePart2)
+	fmt.Println("Total calibration value [PART 1]: ", totalCalibrationValuePart1)
+	fmt.Println("Total calibration value [PART 2]: ", totalCalibrationValuePart2)
 }
 
-func FindCalibrationValuePart1(inputLine string) int {
-	bytes := []byte(inputLine)
-	var result int
+func Part1(inputLines []string) (int, error) {
+	calibration, err := CalibrateLines(inputLines, digitMatcher, POLICY_ERROR)
+	return calibration.Total, err
+}
 
-	for i := 0; ; i++ {
-		if bytes[i] >= 48 && bytes[i] <= 57 {
-			result += (int(bytes[i]) - 48) * 10
-			break
-		}
+func Part2(inputLines []string) (int, error) {
+	calibration, err := CalibrateLines(inputLines, wordMatcher, POLICY_ERROR)
+	return calibration.Total, err
+}
+
+var digitMatcher = NewMatcher(mapDigits())
+var wordMatcher = NewMatcher(mapStringsToDigits())
+
+var ErrNoDigits = errors.New("no digits")
+
+func FindCalibrationValuePart1(inputLine string) (int, error) {
+	return FindCalibrationValuePart2(inputLine, digitMatcher)
+}
+
+func FindCalibrationValuePart2(inputLine string, matcher *Matcher) (int, error) {
+	digits := FindDigits(inputLine, matcher)
+	if len(digits) == 0 {
+		return 0, ErrNoDigits
 	}
+	return digits[0]*10 + digits[len(digits)-1], nil
+}
+
+// Policy decides what happens to a line without a digit
+type Policy int
+
+const (
+	// POLICY_ERROR stops at the line with an error
+	POLICY_ERROR Policy = iota
+	// POLICY_SKIP leaves the line out, it is not counted as calibrated
+	POLICY_SKIP
+	// POLICY_ZERO counts the line as calibrated with the value 0
+	POLICY_ZERO
+)
 
-	for i := len(bytes) - 1; ; i-- {
-		if bytes[i] >= 48 && bytes[i] <= 57 {
-			result += int(bytes[i]) - 48
-			break
+var POLICY_NAMES = []string{"error", "skip", "zero"}
+
+func ParsePolicy(name string) (Policy, error) {
+	for policy, policyName := range POLICY_NAMES {
+		if name == policyName {
+			return Policy(policy), nil
 		}
 	}
+	return 0, fmt.Errorf("invalid policy %q, expected one of: %s", name, strings.Join(POLICY_NAMES, ", "))
+}
+
+func (policy Policy) String() string {
+	if policy < 0 || int(policy) >= len(POLICY_NAMES) {
+		return fmt.Sprintf("Policy(%d)", policy)
+	}
+	return POLICY_NAMES[policy]
+}
 
-	return result
+// Calibration sums up the calibration values of a document
+type Calibration struct {
+	Total   int
+	Lines   int
+	Skipped int
 }
 
-func FindCalibrationValuePart2(inputLine string, stringsToDigits ma) int {
-	digits := FindDigits(inputLine, stringsToDigits)
-	return digits[0]*10 + digits[len(digits)-1]
+// add calibrates a single line according to the policy, lines are numbered from 1
+func (calibration *Calibration) add(lineNumber int, inputLine string, matcher *Matcher, policy Policy) error {
+	value, err := FindCalibrationValuePart2(inputLine, matcher)
+	switch {
+	case err == nil:
+	case policy == POLICY_SKIP:
+		calibration.Skipped++
+		return nil
+	case policy == POLICY_ZERO:
+		value = 0
+	default:
+		return fmt.Errorf("line %d: %w in %q", lineNumber, err, inputLine)
+	}
+
+	calibration.Total += value
+	calibration.Lines++
+	return nil
+}
 
+func CalibrateLines(inputLines []string, matcher *Matcher, policy Policy) (Calibration, error) {
+	calibration := Calibration{}
+	for i, inputLine := range inputLines {
+		if err := calibration.add(i+1, inputLine, matcher, policy); err != nil {
+			return calibration, err
+		}
+	}
+	return calibration, nil
 }
 
-func FindDigits(inputLine string, stringsToDigits ma) []int {
+// Calibrate reads the document line by line, so only a single line is kept in memory however long
+// the document is. When report is not nil, it gets the matches of every line before it is calibrated.
+func Calibrate(reader io.Reader, matcher *Matcher, policy Policy, report func(LineReport)) (Calibration, error) {
+	calibration := Calibration{}
+	buffered := bufio.NewReader(reader)
+
+	for lineNumber := 1; ; lineNumber++ {
+		inputLine, readErr := buffered.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return calibration, readErr
+		}
+		if readErr == io.EOF && inputLine == "" {
+			return calibration, nil
+		}
+
+		inputLine = strings.TrimRight(inputLine, "\r\n")
+		if report != nil {
+			report(reportLine(lineNumber, inputLine, matcher))
+		}
+		if err := calibration.add(lineNumber, inputLine, matcher, policy); err != nil {
+			return calibration, err
+		}
+
+		if readErr == io.EOF {
+			return calibration, nil
+		}
+	}
+}
+
+// FindDigits returns a digit for every position a token starts at, when more tokens start
+// at the same position the longest one wins, e.g. "VIII" and not "V" of the roman numerals.
+// Without overlapping the tokens starting inside the accepted one are skipped as well.
+func FindDigits(inputLine string, matcher *Matcher) []int {
 	var digits []int
-	var substrIndex int
-	var minSubstrIndex int
-	var minSubstr string
-
-	for {
-		minSubstrIndex = -1
-
-		for key, _ := range stringsToDigits {
-			substrIndex = strings.Index(inputLine, key)
-			if substrIndex != -1 && (minSubstrIndex == -1 || substrIndex < minSubstrIndex) {
-				minSubstrIndex = substrIndex
-				minSubstr = key
+	lastPosition, lastEnd := -1, 0
+
+	for _, match := range matcher.FindAll(inputLine) {
+		if match.Position == lastPosition || !matcher.overlapping && match.Position < lastEnd {
+			continue
+		}
+		digits = append(digits, match.Digit)
+		lastPosition, lastEnd = match.Position, match.Position+len(match.Token)
+	}
+	return digits
+}
+
+// Match is an occurrence of a token of the dictionary in a line
+type Match struct {
+	Position int
+	Token    string
+	Digit    int
+}
+
+// Matcher finds all the tokens of a dictionary in a single pass over a line (Aho-Corasick),
+// including the overlapping ones, e.g. "oneight" is both 1 and 8
+type Matcher struct {
+	nodes       []matcherNode
+	overlapping bool
+}
+
+type matcherNode struct {
+	// the fail links are folded into the transitions, so a byte is a single lookup
+	next [256]int32
+	// tokens ending in this node, including the ones reached through the fail links
+	tokens []string
+	digits []int
+}
+
+func NewMatcher(dictionary Dictionary) *Matcher {
+	matcher := &Matcher{nodes: []matcherNode{{}}, overlapping: dictionary.Overlapping}
+
+	// sorted, so that tokens ending at the same position are reported in a stable order
+	tokens := make([]string, 0, len(dictionary.Tokens))
+	for token := range dictionary.Tokens {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	// the trie, 0 is the root, so a missing edge is 0 as well
+	for _, token := range tokens {
+		node := int32(0)
+		for i := 0; i < len(token); i++ {
+			if matcher.nodes[node].next[token[i]] == 0 {
+				matcher.nodes[node].next[token[i]] = int32(len(matcher.nodes))
+				matcher.nodes = append(matcher.nodes, matcherNode{})
 			}
+			node = matcher.nodes[node].next[token[i]]
 		}
+		matcher.nodes[node].tokens = append(matcher.nodes[node].tokens, token)
+		matcher.nodes[node].digits = append(matcher.nodes[node].digits, dictionary.Tokens[token])
+	}
 
-		if minSubstrIndex != -1 {
-			digits = append(digits, stringsToDigits[minSubstr])
-			inputLine = inputLine[(minSubstrIndex + 1):]
-		} else {
-			break
+	// breadth first, so the transitions of the fail node are complete before a node needs them
+	fail := make([]int32, len(matcher.nodes))
+	queue := []int32{}
+	for char := 0; char < 256; char++ {
+		if child := matcher.nodes[0].next[char]; child != 0 {
+			queue = append(queue, child)
 		}
 	}
 
-	return digits
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		failNode := &matcher.nodes[fail[node]]
+		matcher.nodes[node].tokens = append(matcher.nodes[node].tokens, failNode.tokens...)
+		matcher.nodes[node].digits = append(matcher.nodes[node].digits, failNode.digits...)
+
+		for char := 0; char < 256; char++ {
+			child := matcher.nodes[node].next[char]
+			if child == 0 {
+				matcher.nodes[node].next[char] = failNode.next[char]
+				continue
+			}
+			fail[child] = failNode.next[char]
+			queue = append(queue, child)
+		}
+	}
+
+	return matcher
+}
+
+// FindAll returns the matches of the line ordered by their position, the longest first
+func (matcher *Matcher) FindAll(inputLine string) []Match {
+	var matches []Match
+	node := int32(0)
+
+	for i := 0; i < len(inputLine); i++ {
+		node = matcher.nodes[node].next[inputLine[i]]
+		for j, token := range matcher.nodes[node].tokens {
+			matches = append(matches, Match{i - len(token) + 1, token, matcher.nodes[node].digits[j]})
+		}
+	}
+
+	// tokens are found at their end, a longer one may start earlier than a shorter one found before
+	byPosition := func(i, j int) bool {
+		if matches[i].Position != matches[j].Position {
+			return matches[i].Position < matches[j].Position
+		}
+		return len(matches[i].Token) > len(matches[j].Token)
+	}
+	if !sort.SliceIsSorted(matches, byPosition) {
+		sort.SliceStable(matches, byPosition)
+	}
+	return matches
+}
+
+// LineReport lists the tokens matched in a line and the calibration value they make
+type LineReport struct {
+	Line    int
+	Text    string
+	Matches []Match
+	// the calibration value is only defined for a line with a digit
+	Value    int
+	HasValue bool
+}
+
+// Report matches every line separately, e.g. to see how a dictionary reads a text
+func Report(inputLines []string, matcher *Matcher) []LineReport {
+	reports := make([]LineReport, len(inputLines))
+
+	for i, inputLine := range inputLines {
+		reports[i] = reportLine(i+1, inputLine, matcher)
+	}
+	return reports
+}
+
+func reportLine(lineNumber int, inputLine string, matcher *Matcher) LineReport {
+	value, err := FindCalibrationValuePart2(inputLine, matcher)
+	return LineReport{
+		Line:     lineNumber,
+		Text:     inputLine,
+		Matches:  matcher.FindAll(inputLine),
+		Value:    value,
+		HasValue: err == nil,
+	}
+}
+
+func (report LineReport) String() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "line %d: %q ->", report.Line, report.Text)
+	if report.HasValue {
+		fmt.Fprintf(&builder, " %d", report.Value)
+	} else {
+		builder.WriteString(" no digits")
+	}
+
+	for _, match := range report.Matches {
+		fmt.Fprintf(&builder, " %s@%d=%d", match.Token, match.Position, match.Digit)
+	}
+	return builder.String()
+}
+
+// Parse reads the calibration document, one calibration value per line
+func Parse(reader io.Reader) ([]string, error) {
+	return input.Lines(reader)
+}
+
+func Format(writer io.Writer, inputLines []string) error {
+	buffered := bufio.NewWriter(writer)
+	for _, inputLine := range inputLines {
+		fmt.Fprintln(buffered, inputLine)
+	}
+	return buffered.Flush()
+}
+
+// Validate reports the lines with neither a digit nor a spelled out one, which no part can calibrate.
+// A line with only spelled out digits passes, though part 1 rejects it, e.g. the part 2 example.
+func Validate(inputLines []string) []error {
+	if len(inputLines) == 0 {
+		return []error{fmt.Errorf("empty calibration document")}
+	}
+
+	var problems []error
+	for i, inputLine := range inputLines {
+		if len(wordMatcher.FindAll(inputLine)) == 0 {
+			problems = append(problems, fmt.Errorf("line %d: no digit or spelled out digit in %q", i+1, inputLine))
+		}
+	}
+	return problems
 }
 
 func readLines(path string) []string {
@@ -107,8 +384,87 @@ func readLines(path string) []string {
 	return lines
 }
 
-func mapStringsToDigits() ma {
-	return ma{
+// NON_OVERLAPPING_DIRECTIVE is a line of a token file that turns off the overlapping of its tokens
+const NON_OVERLAPPING_DIRECTIVE = "!non-overlapping"
+
+// DICTIONARIES are the built-in digit words, the digits themselves are matched with all of them
+var DICTIONARIES = map[string]Dictionary{
+	"english": {map[string]int{"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9}, true},
+	"german":  {map[string]int{"null": 0, "eins": 1, "zwei": 2, "drei": 3, "vier": 4, "fünf": 5, "sechs": 6, "sieben": 7, "acht": 8, "neun": 9}, true},
+	"polish":  {map[string]int{"zero": 0, "jeden": 1, "dwa": 2, "trzy": 3, "cztery": 4, "pięć": 5, "sześć": 6, "siedem": 7, "osiem": 8, "dziewięć": 9}, true},
+	"roman":   {map[string]int{"I": 1, "II": 2, "III": 3, "IV": 4, "V": 5, "VI": 6, "VII": 7, "VIII": 8, "IX": 9}, false},
+}
+
+// LoadDictionary returns a built-in dictionary by its name or reads a token file, both with the digits added
+func LoadDictionary(nameOrPath string) (Dictionary, error) {
+	words, ok := DICTIONARIES[nameOrPath]
+	if !ok {
+		fd, err := os.Open(nameOrPath)
+		if err != nil {
+			return Dictionary{}, fmt.Errorf("no dictionary %s, neither built-in nor a file: %w", nameOrPath, err)
+		}
+		defer fd.Close()
+
+		if words, err = ReadDictionary(fd); err != nil {
+			return Dictionary{}, fmt.Errorf("%s: %w", nameOrPath, err)
+		}
+	}
+
+	dictionary := mapDigits()
+	dictionary.Overlapping = words.Overlapping
+	for token, digit := range words.Tokens {
+		dictionary.Tokens[token] = digit
+	}
+	return dictionary, nil
+}
+
+// ReadDictionary reads a token file, a token and its digit per line, e.g. "tres 3",
+// empty lines and the lines starting with # are skipped. The tokens overlap
+// unless there is a NON_OVERLAPPING_DIRECTIVE line.
+func ReadDictionary(reader io.Reader) (Dictionary, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return Dictionary{}, err
+	}
+
+	dictionary := Dictionary{Tokens: map[string]int{}, Overlapping: true}
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == NON_OVERLAPPING_DIRECTIVE {
+			dictionary.Overlapping = false
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return Dictionary{}, fmt.Errorf("line %d: expected a token and a digit, got %q", i+1, line)
+		}
+
+		digit, err := strconv.Atoi(fields[1])
+		if err != nil || digit < 0 || digit > 9 {
+			return Dictionary{}, fmt.Errorf("line %d: invalid digit %q", i+1, fields[1])
+		}
+		if _, ok := dictionary.Tokens[fields[0]]; ok {
+			return Dictionary{}, fmt.Errorf("line %d: duplicate token %q", i+1, fields[0])
+		}
+		dictionary.Tokens[fields[0]] = digit
+	}
+
+	if len(dictionary.Tokens) == 0 {
+		return Dictionary{}, errors.New("empty dictionary")
+	}
+	return dictionary, nil
+}
+
+func mapDigits() Dictionary {
+	return Dictionary{map[string]int{"0": 0, "1": 1, "2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9}, true}
+}
+
+func mapStringsToDigits() Dictionary {
+	return Dictionary{Overlapping: true, Tokens: map[string]int{
 		"zero":  0,
 		"one":   1,
 		"two":   2,
@@ -129,5 +485,5 @@ func mapStringsToDigits() ma {
 		"7":     7,
 		"8":     8,
 		"9":     9,
-	}
+	}}
 }
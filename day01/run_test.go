@@ -0,0 +1,133 @@
+package day01
+
+import (
+	"aoc2023/sampletest"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFormatRoundTrip(t *testing.T) {
+	sampletest.RoundTrip(t, Samples, Parse, Format, Part1, Part2)
+}
+
+func TestFindDigits(t *testing.T) {
+	tests := []struct {
+		dictionary string
+		line       string
+		value      int
+	}{
+		{"english", "oneight", 18},
+		{"english", "eightwothree", 83},
+		{"roman", "VIII", 88},
+		{"roman", "IV", 44},
+		{"roman", "xIXyVII", 97},
+	}
+
+	for _, test := range tests {
+		dictionary, err := LoadDictionary(test.dictionary)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		value, err := FindCalibrationValuePart2(test.line, NewMatcher(dictionary))
+		if err != nil || value != test.value {
+			t.Errorf("%s %q: calibration value %d (%v), expected %d", test.dictionary, test.line, value, err, test.value)
+		}
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	const DOCUMENT = "1abc2\r\nnothing here\nx3y"
+	tests := []struct {
+		policy      Policy
+		calibration Calibration
+		err         string
+	}{
+		{POLICY_ERROR, Calibration{Total: 12, Lines: 1}, `line 2: no digits in "nothing here"`},
+		{POLICY_SKIP, Calibration{Total: 45, Lines: 2, Skipped: 1}, ""},
+		{POLICY_ZERO, Calibration{Total: 45, Lines: 3}, ""},
+	}
+
+	for _, test := range tests {
+		calibration, err := Calibrate(strings.NewReader(DOCUMENT), digitMatcher, test.policy, nil)
+		if calibration != test.calibration {
+			t.Errorf("policy %s: %+v, expected %+v", test.policy, calibration, test.calibration)
+		}
+
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("policy %s: %v", test.policy, err)
+		case test.err != "" && (err == nil || err.Error() != test.err):
+			t.Errorf("policy %s: error %v, expected %q", test.policy, err, test.err)
+		case test.err != "" && !errors.Is(err, ErrNoDigits):
+			t.Errorf("policy %s: error %v is not ErrNoDigits", test.policy, err)
+		}
+	}
+}
+
+func TestPartsWithoutDigits(t *testing.T) {
+	lines := []string{"two1nine", "abcone", "xyz"}
+
+	if _, err := Part1(lines); !errors.Is(err, ErrNoDigits) || !strings.HasPrefix(err.Error(), "line 2: ") {
+		t.Errorf("part 1: error %v, expected ErrNoDigits on line 2", err)
+	}
+	if _, err := Part2(lines); !errors.Is(err, ErrNoDigits) || !strings.HasPrefix(err.Error(), "line 3: ") {
+		t.Errorf("part 2: error %v, expected ErrNoDigits on line 3", err)
+	}
+}
+
+func TestCalibrateReport(t *testing.T) {
+	var reports []LineReport
+	_, err := Calibrate(strings.NewReader("a1\nb\n"), digitMatcher, POLICY_SKIP, func(report LineReport) {
+		reports = append(reports, report)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []LineReport{
+		{Line: 1, Text: "a1", Matches: []Match{{1, "1", 1}}, Value: 11, HasValue: true},
+		{Line: 2, Text: "b"},
+	}
+	if !reflect.DeepEqual(reports, expected) {
+		t.Errorf("reports %+v, expected %+v", reports, expected)
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	english, err := LoadDictionary("english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	roman, err := LoadDictionary("roman")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dictionary Dictionary
+		line       string
+		matches    []Match
+		digits     []int
+	}{
+		{english, "twone", []Match{{0, "two", 2}, {2, "one", 1}}, []int{2, 1}},
+		{english, "eightwo", []Match{{0, "eight", 8}, {4, "two", 2}}, []int{8, 2}},
+		{english, "xoneightx", []Match{{1, "one", 1}, {3, "eight", 8}}, []int{1, 8}},
+		// all the tokens are found, but a roman numeral is read as a whole
+		{roman, "VIII", []Match{{0, "VIII", 8}, {0, "VII", 7}, {0, "VI", 6}, {0, "V", 5},
+			{1, "III", 3}, {1, "II", 2}, {1, "I", 1}, {2, "II", 2}, {2, "I", 1}, {3, "I", 1}}, []int{8}},
+		{roman, "IV-II", []Match{{0, "IV", 4}, {0, "I", 1}, {1, "V", 5}, {3, "II", 2}, {3, "I", 1}, {4, "I", 1}}, []int{4, 2}},
+	}
+
+	for _, test := range tests {
+		matcher := NewMatcher(test.dictionary)
+		if matches := matcher.FindAll(test.line); !reflect.DeepEqual(matches, test.matches) {
+			t.Errorf("matches of %q: %v, expected %v", test.line, matches, test.matches)
+		}
+		if digits := FindDigits(test.line, matcher); !reflect.DeepEqual(digits, test.digits) {
+			t.Errorf("digits of %q: %v, expected %v", test.line, digits, test.digits)
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package day02
 
 import (
+	"aoc2023/input"
 	"bufio"
+	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -15,143 +19,444 @@ type Game struct {
 	CubeSets []CubeSet
 }
 
-type CubeSet struct {
-	RedCubes   int
-	GreenCubes int
-	BlueCubes  int
-}
+// CubeSet is the number of cubes of every color, colors are whatever the input names
+type CubeSet map[string]int
 
+const TITLE = "Cube Conundrum"
 const INPUT_FILE_PATH = "day02/input.txt"
-const MAX_RED_CUBES = 12
-const MAX_GREEN_CUBES = 13
-const MAX_BLUE_CUBES = 14
+
+//go:embed samples
+var Samples embed.FS
+
+// DEFAULT_COLORS are the colors of the puzzle, a cube set lists them first
+var DEFAULT_COLORS = []string{"red", "green", "blue"}
+
+// DEFAULT_BAG is the bag the elf asks about in part 1
+var DEFAULT_BAG = CubeSet{"red": 12, "green": 13, "blue": 14}
 
 func Run() {
 	// Read input file (from input.txt)
-	var sumOfPossibleGameIds, sumOfPossibleGamePowers int
 	inputLines := readLines(INPUT_FILE_PATH)
-	games := make([]*Game, len(inputLines))
 
-	for _, inputLine := range inputLines {
-		game, err := ParseGameString(inputLine)
-		if err != nil {
-			panic(err)
+	sumOfPossibleGameIds, err := Part1(inputLines)
+	if err != nil {
+		panic(err)
+	}
+
+	sumOfPossibleGamePowers, err := Part2(inputLines)
+	if err != nil {
+		panic(err)
+	}
+
+	// Print result
+	fmt.Println("Sum of possible game IDs 	 [PART1]: ", sumOfPossibleGameIds)
+	fmt.Println("Sum of possible game powers [PART2]: ", sumOfPossibleGamePowers)
+}
+
+func Part1(inputLines []string) (int, error) {
+	games, err := parseGames(inputLines)
+	if err != nil {
+		return 0, err
+	}
+
+	sumOfPossibleGameIds := 0
+	for _, game := range games {
+		if IsGamePossible(game, DEFAULT_BAG) {
+			sumOfPossibleGameIds += game.Id
 		}
+	}
+	return sumOfPossibleGameIds, nil
+}
 
-		games[game.Id-1] = game
+func Part2(inputLines []string) (int, error) {
+	games, err := parseGames(inputLines)
+	if err != nil {
+		return 0, err
 	}
 
+	colors := GameColors(games)
+	sumOfPossibleGamePowers := 0
 	for _, game := range games {
 		minCubeSet := MinGameCubeSet(game)
-		minCubeSetPower := CubeSetPower(minCubeSet)
+		sumOfPossibleGamePowers += CubeSetPower(minCubeSet, colors)
+	}
+	return sumOfPossibleGamePowers, nil
+}
 
-		if IsGamePossible(game) {
-			sumOfPossibleGameIds += game.Id
+// parseGames returns the games sorted by their IDs, which do not need to be contiguous
+// or in order, but a duplicate ID is an error as the answers would be ambiguous
+func parseGames(inputLines []string) ([]*Game, error) {
+	gamesById := make(map[int]*Game)
+	gameLines := make(map[int]int)
+
+	for i, inputLine := range inputLines {
+		game, err := ParseGameString(inputLine)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
 		}
-		sumOfPossibleGamePowers += minCubeSetPower
+
+		if firstLine, ok := gameLines[game.Id]; ok {
+			return nil, fmt.Errorf("line %d: game %d is already defined on line %d", i+1, game.Id, firstLine)
+		}
+		gamesById[game.Id] = game
+		gameLines[game.Id] = i + 1
 	}
 
-	// Print result
-	println("Sum of possible game IDs 	 [PART1]: ", sumOfPossibleGameIds)
-	println("Sum of possible game powers [PART2]: ", sumOfPossibleGamePowers)
+	return sortedGames(gamesById), nil
 }
 
-func CubeSetPower(cubeSet *CubeSet) int {
-	return cubeSet.RedCubes * cubeSet.GreenCubes * cubeSet.BlueCubes
+func sortedGames(gamesById map[int]*Game) []*Game {
+	games := make([]*Game, 0, len(gamesById))
+	for _, game := range gamesById {
+		games = append(games, game)
+	}
+
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].Id < games[j].Id
+	})
+	return games
 }
 
-func MinGameCubeSet(game *Game) *CubeSet {
-	minCubeSet := CubeSet{
-		RedCubes:   0,
-		GreenCubes: 0,
-		BlueCubes:  0,
+// CubeSetPower multiplies the numbers of cubes of the colors, a missing color makes it 0
+func CubeSetPower(cubeSet CubeSet, colors []string) int {
+	power := 1
+	for _, color := range colors {
+		power *= cubeSet[color]
 	}
+	return power
+}
+
+func MinGameCubeSet(game *Game) CubeSet {
+	minCubeSet := CubeSet{}
 
 	for _, cubeSet := range game.CubeSets {
-		if cubeSet.RedCubes > minCubeSet.RedCubes {
-			minCubeSet.RedCubes = cubeSet.RedCubes
-		}
-		if cubeSet.GreenCubes > minCubeSet.GreenCubes {
-			minCubeSet.GreenCubes = cubeSet.GreenCubes
-		}
-		if cubeSet.BlueCubes > minCubeSet.BlueCubes {
-			minCubeSet.BlueCubes = cubeSet.BlueCubes
+		for color, count := range cubeSet {
+			if count > minCubeSet[color] {
+				minCubeSet[color] = count
+			}
 		}
 	}
 
-	fmt.Println(game)
-	fmt.Println(minCubeSet)
+	return minCubeSet
+}
 
-	return &minCubeSet
+// GameColors are the colors seen in any of the games, the power of a cube set is taken over them
+func GameColors(games []*Game) []string {
+	colors := []string{}
+	for color := range MinimalBag(games) {
+		colors = append(colors, color)
+	}
+	sort.Strings(colors)
+	return colors
 }
 
-func IsGamePossible(game *Game) bool {
+// IsGamePossible checks the game against the cubes in the bag, a color missing in the bag has no cubes
+func IsGamePossible(game *Game, bag CubeSet) bool {
 	for _, cubeSet := range game.CubeSets {
-		if cubeSet.RedCubes > MAX_RED_CUBES ||
-			cubeSet.GreenCubes > MAX_GREEN_CUBES ||
-			cubeSet.BlueCubes > MAX_BLUE_CUBES {
-			return false
+		for color, count := range cubeSet {
+			if count > bag[color] {
+				return false
+			}
 		}
 	}
 
 	return true
 }
 
-func ParseGameString(gameString string) (*Game, error) {
-	prefixRegex := regexp.MustCompile(`^Game (\d+): `)
-	matches := prefixRegex.FindStringSubmatch(gameString)
+// MinimalBag is the smallest bag all the games are possible with
+func MinimalBag(games []*Game) CubeSet {
+	minimalBag := CubeSet{}
+	for _, game := range games {
+		for color, count := range MinGameCubeSet(game) {
+			minimalBag[color] = max(minimalBag[color], count)
+		}
+	}
+	return minimalBag
+}
 
-	var cubeSets []CubeSet
-	var prefix string
+// RulingOut returns the games that are not possible with the bag
+func RulingOut(games []*Game, bag CubeSet) []*Game {
+	var impossibleGames []*Game
+	for _, game := range games {
+		if !IsGamePossible(game, bag) {
+			impossibleGames = append(impossibleGames, game)
+		}
+	}
+	return impossibleGames
+}
 
-	if len(matches) != 2 {
-		return nil, errors.New("invalid game string")
+// ParseCubeSet parses the cubes of a single set, e.g. "3 blue, 4 red"
+func ParseCubeSet(cubeSetString string) (CubeSet, error) {
+	parser, err := newGameParser(cubeSetString)
+	if err != nil {
+		return nil, err
 	}
 
-	prefix = matches[0]
-	gameId, err := strconv.Atoi(matches[1])
+	cubeSet, err := parser.parseCubeSet()
 	if err != nil {
-		return nil, errors.New("invalid game string")
+		return nil, err
+	}
+	if _, err := parser.expect(TOKEN_END, ""); err != nil {
+		return nil, err
 	}
+	return cubeSet, nil
+}
 
-	parsedString, _ := strings.CutPrefix(gameString, prefix)
-	cubeSetsStrings := strings.Split(parsedString, "; ")
+// ParseGameString parses a game, e.g. "Game 1: 3 blue, 4 red; 1 red, 2 green", the errors
+// point at the column of the offending token
+func ParseGameString(gameString string) (*Game, error) {
+	parser, err := newGameParser(gameString)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, cubeSetString := range cubeSetsStrings {
-		cubeStrings := strings.Split(cubeSetString, ", ")
-		cubeSet := CubeSet{}
+	if _, err := parser.expect(TOKEN_WORD, "Game"); err != nil {
+		return nil, err
+	}
+	idToken, err := parser.expect(TOKEN_NUMBER, "")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parser.expect(TOKEN_PUNCTUATION, ":"); err != nil {
+		return nil, err
+	}
 
-		for _, cubeString := range cubeStrings {
-			countAndColor := strings.Split(cubeString, " ")
-			if len(countAndColor) != 2 {
-				return nil, errors.New("invalid game string")
-			}
+	game := &Game{Id: idToken.number}
+	for {
+		cubeSet, err := parser.parseCubeSet()
+		if err != nil {
+			return nil, err
+		}
+		game.CubeSets = append(game.CubeSets, cubeSet)
+
+		if parser.peek().text != ";" {
+			break
+		}
+		parser.next()
+	}
+
+	if _, err := parser.expect(TOKEN_END, ""); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+type tokenKind int
+
+const (
+	TOKEN_WORD tokenKind = iota
+	TOKEN_NUMBER
+	TOKEN_PUNCTUATION
+	TOKEN_END
+)
 
-			count, err := strconv.Atoi(countAndColor[0])
-			color := countAndColor[1]
+var TOKEN_KIND_NAMES = []string{"a word", "a number", "a punctuation mark", "the end of the line"}
+
+type token struct {
+	kind   tokenKind
+	text   string
+	number int
+	// columns are counted from 1, as in the editors
+	column int
+}
+
+func (token token) String() string {
+	if token.kind == TOKEN_END {
+		return TOKEN_KIND_NAMES[TOKEN_END]
+	}
+	return fmt.Sprintf("%q", token.text)
+}
+
+func tokenize(line string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(line); {
+		start := i
+		switch char := line[i]; {
+		case char == ' ' || char == '\t':
+			i++
+			continue
+		case char == ':' || char == ',' || char == ';':
+			i++
+			tokens = append(tokens, token{kind: TOKEN_PUNCTUATION, text: line[start:i], column: start + 1})
+		case char >= '0' && char <= '9':
+			for i < len(line) && line[i] >= '0' && line[i] <= '9' {
+				i++
+			}
+			number, err := strconv.Atoi(line[start:i])
 			if err != nil {
-				return nil, errors.New("invalid game string")
+				return nil, fmt.Errorf("column %d: invalid number %q", start+1, line[start:i])
 			}
-
-			switch color {
-			case "red":
-				cubeSet.RedCubes = count
-			case "green":
-				cubeSet.GreenCubes = count
-			case "blue":
-				cubeSet.BlueCubes = count
-			default:
-				return nil, errors.New("invalid game string")
+			tokens = append(tokens, token{kind: TOKEN_NUMBER, text: line[start:i], number: number, column: start + 1})
+		case isLetter(char):
+			for i < len(line) && isLetter(line[i]) {
+				i++
 			}
+			tokens = append(tokens, token{kind: TOKEN_WORD, text: line[start:i], column: start + 1})
+		default:
+			return nil, fmt.Errorf("column %d: unexpected character %q", start+1, char)
+		}
+	}
+
+	return append(tokens, token{kind: TOKEN_END, column: len(line) + 1}), nil
+}
+
+func isLetter(char byte) bool {
+	return char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char == '_' || char == '-'
+}
+
+type gameParser struct {
+	tokens   []token
+	position int
+}
+
+func newGameParser(line string) (*gameParser, error) {
+	tokens, err := tokenize(line)
+	if err != nil {
+		return nil, err
+	}
+	return &gameParser{tokens: tokens}, nil
+}
+
+// peek returns the current token, the last one is always the end of the line
+func (parser *gameParser) peek() token {
+	return parser.tokens[parser.position]
+}
+
+func (parser *gameParser) next() token {
+	current := parser.peek()
+	if current.kind != TOKEN_END {
+		parser.position++
+	}
+	return current
+}
+
+// expect consumes a token of the kind, and of the text unless it is empty
+func (parser *gameParser) expect(kind tokenKind, text string) (token, error) {
+	current := parser.peek()
+	if current.kind != kind || (text != "" && current.text != text) {
+		expected := TOKEN_KIND_NAMES[kind]
+		if text != "" {
+			expected = fmt.Sprintf("%q", text)
 		}
+		return current, fmt.Errorf("column %d: expected %s, got %s", current.column, expected, current)
+	}
+	return parser.next(), nil
+}
+
+func (parser *gameParser) parseCubeSet() (CubeSet, error) {
+	cubeSet := CubeSet{}
 
-		cubeSets = append(cubeSets, cubeSet)
+	for {
+		countToken, err := parser.expect(TOKEN_NUMBER, "")
+		if err != nil {
+			return nil, err
+		}
+		colorToken, err := parser.expect(TOKEN_WORD, "")
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := cubeSet[colorToken.text]; ok {
+			return nil, fmt.Errorf("column %d: color %s is repeated in the set", colorToken.column, colorToken.text)
+		}
+		cubeSet[colorToken.text] = countToken.number
+
+		if parser.peek().text != "," {
+			return cubeSet, nil
+		}
+		parser.next()
 	}
+}
+
+// Validate reports every invalid game, the duplicate IDs and the IDs missing between 1 and the highest one
+func Validate(inputLines []string) []error {
+	var problems []error
+	gameLines := make(map[int]int)
+	maxId := 0
+
+	for i, inputLine := range inputLines {
+		game, err := ParseGameString(inputLine)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("line %d: %w", i+1, err))
+			continue
+		}
+
+		if firstLine, ok := gameLines[game.Id]; ok {
+			problems = append(problems, fmt.Errorf("line %d: game %d is already defined on line %d", i+1, game.Id, firstLine))
+			continue
+		}
+		gameLines[game.Id] = i + 1
+		maxId = max(maxId, game.Id)
+	}
+
+	var missingIds []string
+	for id := 1; id <= maxId; id++ {
+		if _, ok := gameLines[id]; !ok {
+			missingIds = append(missingIds, strconv.Itoa(id))
+		}
+	}
+	if len(missingIds) > 0 {
+		problems = append(problems, fmt.Errorf("missing games: %s", strings.Join(missingIds, ", ")))
+	}
+
+	if len(inputLines) == 0 {
+		problems = append(problems, errors.New("no games"))
+	}
+	return problems
+}
+
+func Parse(reader io.Reader) ([]*Game, error) {
+	inputLines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseGames(inputLines)
+}
+
+// Format writes the games in the canonical form, the cubes of a set are in the red, green, blue
+// order followed by the other colors alphabetically, a color with 0 cubes is written as well
+func Format(writer io.Writer, games []*Game) error {
+	buffered := bufio.NewWriter(writer)
+
+	for _, game := range games {
+		cubeSetStrings := make([]string, len(game.CubeSets))
+		for i, cubeSet := range game.CubeSets {
+			cubeSetStrings[i] = cubeSetString(cubeSet)
+		}
+
+		fmt.Fprintf(buffered, "Game %d: %s\n", game.Id, strings.Join(cubeSetStrings, "; "))
+	}
+
+	return buffered.Flush()
+}
+
+func cubeSetString(cubeSet CubeSet) string {
+	var cubeStrings []string
+	for _, color := range orderedColors(cubeSet) {
+		if count, ok := cubeSet[color]; ok {
+			cubeStrings = append(cubeStrings, fmt.Sprintf("%d %s", count, color))
+		}
+	}
+
+	return strings.Join(cubeStrings, ", ")
+}
+
+func orderedColors(cubeSet CubeSet) []string {
+	var otherColors []string
+	for color := range cubeSet {
+		if !slices.Contains(DEFAULT_COLORS, color) {
+			otherColors = append(otherColors, color)
+		}
+	}
+	sort.Strings(otherColors)
+
+	return append(slices.Clone(DEFAULT_COLORS), otherColors...)
+}
 
-	return &Game{
-		Id:       gameId,
-		CubeSets: cubeSets,
-	}, nil
+// String writes the cube set in the canonical form, e.g. "4 red, 2 green"
+func (cubeSet CubeSet) String() string {
+	return cubeSetString(cubeSet)
 }
 
 func readLines(path string) []string {
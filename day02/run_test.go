@@ -0,0 +1,145 @@
+package day02
+
+import (
+	"aoc2023/sampletest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFormatRoundTrip(t *testing.T) {
+	sampletest.RoundTrip(t, Samples, Parse, Format, Part1, Part2)
+
+	tests := []string{
+		"Game 2: 0 red",
+		"Game 1: 0 red, 2 blue; 3 green\nGame 3: 1 yellow, 0 blue",
+	}
+	for _, test := range tests {
+		sampletest.CheckRoundTrip(t, test, Parse, Format)
+	}
+}
+
+func sampleGames(t *testing.T) []*Game {
+	t.Helper()
+	games, err := Parse(strings.NewReader(sampletest.Load(t, Samples)[0].Input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return games
+}
+
+func gameIds(games []*Game) []int {
+	ids := []int{}
+	for _, game := range games {
+		ids = append(ids, game.Id)
+	}
+	return ids
+}
+
+func TestMinimalBag(t *testing.T) {
+	games := sampleGames(t)
+
+	expected := CubeSet{"red": 20, "green": 13, "blue": 15}
+	if bag := MinimalBag(games); !reflect.DeepEqual(bag, expected) {
+		t.Errorf("minimal bag %s, expected %s", bag, expected)
+	}
+	if impossible := RulingOut(games, MinimalBag(games)); len(impossible) != 0 {
+		t.Errorf("games %v rule out their own minimal bag", gameIds(impossible))
+	}
+}
+
+func TestRulingOut(t *testing.T) {
+	games := sampleGames(t)
+	tests := []struct {
+		bag        string
+		impossible []int
+	}{
+		{"12 red, 13 green, 14 blue", []int{3, 4}},
+		{"20 red, 13 green, 15 blue", []int{}},
+		{"4 red, 3 green, 6 blue", []int{3, 4, 5}},
+		{"100 red", []int{1, 2, 3, 4, 5}},
+	}
+
+	for _, test := range tests {
+		bag, err := ParseCubeSet(test.bag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if impossible := gameIds(RulingOut(games, bag)); !reflect.DeepEqual(impossible, test.impossible) {
+			t.Errorf("bag %s is ruled out by %v, expected %v", test.bag, impossible, test.impossible)
+		}
+	}
+}
+
+func TestIsGamePossible(t *testing.T) {
+	bag := CubeSet{"red": 2, "yellow": 3}
+	tests := []struct {
+		game     string
+		possible bool
+	}{
+		{"Game 1: 3 yellow, 2 red; 1 yellow", true},
+		{"Game 2: 4 yellow", false},
+		{"Game 3: 1 red, 1 blue", false},
+		{"Game 4: 0 blue, 2 red", true},
+	}
+
+	for _, test := range tests {
+		game, err := ParseGameString(test.game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if possible := IsGamePossible(game, bag); possible != test.possible {
+			t.Errorf("%q with %s: possible %v, expected %v", test.game, bag, possible, test.possible)
+		}
+	}
+}
+
+func TestParseGameStringErrors(t *testing.T) {
+	tests := []struct {
+		game string
+		err  string
+	}{
+		{"Gam 1: 3 red", `column 1: expected "Game", got "Gam"`},
+		{"Game x: 3 red", `column 6: expected a number, got "x"`},
+		{"Game 1 3 red", `column 8: expected ":", got "3"`},
+		{"Game 1: 3 red,", "column 15: expected a number, got the end of the line"},
+		{"Game 1: 3 red; 4", "column 17: expected a word, got the end of the line"},
+		{"Game 1: 3 red, 2 red", "column 18: color red is repeated in the set"},
+		{"Game 1: 3 red!", "column 14: unexpected character '!'"},
+		{"Game 1: 3 red 4 blue", `column 15: expected the end of the line, got "4"`},
+	}
+
+	for _, test := range tests {
+		_, err := ParseGameString(test.game)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%q: error %v, expected %q", test.game, err, test.err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		lines    []string
+		problems []string
+	}{
+		{[]string{"Game 1: 1 red", "Game 2: 2 red"}, nil},
+		{[]string{"Game 2: 1 red", "Game 1: 2 red"}, nil},
+		{[]string{"Game 1: 1 red", "Game 1: 2 red"}, []string{"line 2: game 1 is already defined on line 1"}},
+		{[]string{"Game 1: 1 red", "Game 4: 2 red"}, []string{"missing games: 2, 3"}},
+		{[]string{"Game 2: 1 red", "Game 2 2 red"}, []string{
+			`line 2: column 8: expected ":", got "2"`,
+			"missing games: 1",
+		}},
+		{nil, []string{"no games"}},
+	}
+
+	for _, test := range tests {
+		var problems []string
+		for _, problem := range Validate(test.lines) {
+			problems = append(problems, problem.Error())
+		}
+		if !reflect.DeepEqual(problems, test.problems) {
+			t.Errorf("%q: problems %q, expected %q", test.lines, problems, test.problems)
+		}
+	}
+}
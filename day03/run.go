@@ -1,18 +1,23 @@
 package day03
 
 import (
+	"aoc2023/input"
 	"bufio"
+	"embed"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 )
 
+const TITLE = "Gear Ratios"
 const INPUT_FILE_PATH = "day03/input.txt"
 
-type Asterisk struct {
-	X               int
-	Y               int
-	AdjacentNumbers []int
-}
+//go:embed samples
+var Samples embed.FS
 
 type Coords struct {
 	X int
@@ -32,154 +37,191 @@ type Bytemap struct {
 	ColSize int
 }
 
-type Asterisks map[Coords][]int
+// Symbol is a character of the schematic other than a digit or a dot
+type Symbol struct {
+	Coords
+	Char byte
+}
+
+// SymbolIndex maps the coordinates of the symbols to their characters
+type SymbolIndex map[Coords]byte
 
 func Run() {
 	bytemap := readBytemap(INPUT_FILE_PATH)
-	numbers := FindAllNumbers(bytemap)
-	asterisks := FindAllAsterisks(bytemap)
-	var sum1, sum2 int
 
 	// Part 1
-	for _, coords := range *numbers {
-		if IsPartNumber(bytemap, coords) {
-			sum1 += coords.Val
-		}
-	}
+	sum1 := SumOfPartNumbers(bytemap)
 	fmt.Println("Sum of part numbers [PART 1]: ", sum1)
 
 	// Part 2
+	sum2 := SumOfGearRatios(bytemap)
+	fmt.Println("Sum of gear ratios [PART 2]: ", sum2)
+}
+
+func Part1(lines []string) (int, error) {
+	return SumOfPartNumbers(linesToBytemap(lines)), nil
+}
+
+func Part2(lines []string) (int, error) {
+	return SumOfGearRatios(linesToBytemap(lines)), nil
+}
+
+func SumOfPartNumbers(bytemap *Bytemap) int {
+	numbers := FindAllNumbers(bytemap)
+	index := IndexSymbols(bytemap)
+	sum := 0
+
 	for _, coords := range *numbers {
-		adjacent, asteriskCoords := IsAdjacentToAsterisk(bytemap, coords)
-		if adjacent {
-			asterisks[asteriskCoords] = append(asterisks[asteriskCoords], coords.Val)
+		if IsPartNumber(index, coords) {
+			sum += coords.Val
 		}
 	}
 
-	for _, asterisk := range asterisks {
-		if len(asterisk) == 2 {
-			sum2 += asterisk[0] * asterisk[1]
-		}
-	}
+	return sum
+}
 
-	fmt.Println("Sum of gear ratios [PART 2]: ", sum2)
+// SumOfGearRatios counts a number in every gear it is adjacent to, not only the first one
+func SumOfGearRatios(bytemap *Bytemap) int {
+	return SumOfGears(bytemap, DEFAULT_GEAR_RULE)
 }
 
-func findAsterisk(asterisks []Asterisk, coords Coords) *Asterisk {
-	for _, asterisk := range asterisks {
-		if asterisk.X == coords.X && asterisk.Y == coords.Y {
-			return &asterisk
-		}
-	}
-	return nil
+// GearRule tells which symbols are gears, by the numbers adjacent to them, and how the numbers make a ratio
+type GearRule struct {
+	Symbol    byte
+	Count     int
+	AtLeast   bool
+	Aggregate string
 }
 
-func IsAdjacentToAsterisk(bytemap *Bytemap, coords Number) (bool, Coords) {
-	// previous row
-	if coords.X != 0 {
-		startOffset, endOffset := findStartOffset(bytemap, coords), findEndOffset(bytemap, coords)
+// DEFAULT_GEAR_RULE is the gear of the puzzle, "*" with exactly two numbers multiplied
+var DEFAULT_GEAR_RULE = GearRule{Symbol: '*', Count: 2, Aggregate: "product"}
 
-		for columnIndex := startOffset; columnIndex < endOffset; columnIndex++ {
-			if isAsterisk(bytemap.Bytes[coords.X-1][columnIndex]) {
-				return true, Coords{X: coords.X - 1, Y: columnIndex}
-			}
+var AGGREGATES = map[string]func(values []int) int{
+	"product": func(values []int) int {
+		product := 1
+		for _, value := range values {
+			product *= value
+		}
+		return product
+	},
+	"sum": func(values []int) int {
+		sum := 0
+		for _, value := range values {
+			sum += value
 		}
+		return sum
+	},
+	"max": func(values []int) int {
+		return slices.Max(values)
+	},
+}
+
+// ParseGearRule parses a rule written as <symbol>=<count>:<aggregate> for exactly count numbers,
+// or <symbol>>=<count>:<aggregate> for at least count numbers, e.g. "*=2:product" or "#>=3:sum"
+func ParseGearRule(ruleString string) (GearRule, error) {
+	rule := GearRule{}
+	if len(ruleString) < 1 || !isSymbolChar(ruleString[0]) {
+		return rule, fmt.Errorf("invalid gear rule %q: expected a symbol first", ruleString)
 	}
+	rule.Symbol = ruleString[0]
 
-	// current row
-	if coords.Y != 0 {
-		if isAsterisk(bytemap.Bytes[coords.X][coords.Y-1]) {
-			return true, Coords{X: coords.X, Y: coords.Y - 1}
-		}
+	countString, found := strings.CutPrefix(ruleString[1:], ">=")
+	if found {
+		rule.AtLeast = true
+	} else if countString, found = strings.CutPrefix(ruleString[1:], "="); !found {
+		return rule, fmt.Errorf("invalid gear rule %q: expected = or >= after the symbol", ruleString)
 	}
 
-	if coords.Y+coords.Len < bytemap.ColSize {
-		if isAsterisk(bytemap.Bytes[coords.X][coords.Y+coords.Len]) {
-			return true, Coords{X: coords.X, Y: coords.Y + coords.Len}
-		}
+	countString, rule.Aggregate, found = strings.Cut(countString, ":")
+	if !found {
+		return rule, fmt.Errorf("invalid gear rule %q: expected :<aggregate> after the count", ruleString)
 	}
 
-	// next row
-	if coords.X != bytemap.ColSize-1 {
-		startOffset, endOffset := findStartOffset(bytemap, coords), findEndOffset(bytemap, coords)
+	count, err := strconv.Atoi(countString)
+	if err != nil || count < 1 {
+		return rule, fmt.Errorf("invalid gear rule %q: the count must be a positive number", ruleString)
+	}
+	rule.Count = count
 
-		for columnIndex := startOffset; columnIndex < endOffset; columnIndex++ {
-			if isAsterisk(bytemap.Bytes[coords.X+1][columnIndex]) {
-				return true, Coords{X: coords.X + 1, Y: columnIndex}
-			}
-		}
+	if _, ok := AGGREGATES[rule.Aggregate]; !ok {
+		return rule, fmt.Errorf("invalid gear rule %q: unknown aggregate %s", ruleString, rule.Aggregate)
 	}
+	return rule, nil
+}
 
-	return false, Coords{}
+func (rule GearRule) String() string {
+	operator := "="
+	if rule.AtLeast {
+		operator = ">="
+	}
+	return fmt.Sprintf("%c%s%d:%s", rule.Symbol, operator, rule.Count, rule.Aggregate)
+}
 
+func (rule GearRule) Matches(adjacentNumbers []int) bool {
+	if rule.AtLeast {
+		return len(adjacentNumbers) >= rule.Count
+	}
+	return len(adjacentNumbers) == rule.Count
 }
 
-func IsPartNumber(bytemap *Bytemap, coords Number) bool {
-	// previous row
-	if coords.X != 0 {
-		startOffset, endOffset := findStartOffset(bytemap, coords), findEndOffset(bytemap, coords)
+// SumOfGears sums up the ratios of the gears of the rule
+func SumOfGears(bytemap *Bytemap, rule GearRule) int {
+	numbers := FindAllNumbers(bytemap)
+	index := IndexSymbols(bytemap)
+	gears := make(map[Coords][]int)
+	sum := 0
 
-		for columnIndex := startOffset; columnIndex < endOffset; columnIndex++ {
-			if isSymbolChar(bytemap.Bytes[coords.X-1][columnIndex]) {
-				return true
+	for _, coords := range *numbers {
+		for _, symbol := range index.AdjacentSymbols(coords) {
+			if symbol.Char == rule.Symbol {
+				gears[symbol.Coords] = append(gears[symbol.Coords], coords.Val)
 			}
 		}
 	}
 
-	// current row
-	if coords.Y != 0 {
-		if isSymbolChar(bytemap.Bytes[coords.X][coords.Y-1]) {
-			return true
+	aggregate := AGGREGATES[rule.Aggregate]
+	for _, adjacentNumbers := range gears {
+		if rule.Matches(adjacentNumbers) {
+			sum += aggregate(adjacentNumbers)
 		}
 	}
 
-	if coords.Y+coords.Len < bytemap.ColSize {
-		if isSymbolChar(bytemap.Bytes[coords.X][coords.Y+coords.Len]) {
-			return true
-		}
-	}
+	return sum
+}
+
+func IsPartNumber(index SymbolIndex, coords Number) bool {
+	return len(index.AdjacentSymbols(coords)) > 0
+}
 
-	// next row
-	if coords.X != bytemap.ColSize-1 {
-		startOffset, endOffset := findStartOffset(bytemap, coords), findEndOffset(bytemap, coords)
+func IndexSymbols(bytemap *Bytemap) SymbolIndex {
+	index := make(SymbolIndex)
 
-		for columnIndex := startOffset; columnIndex < endOffset; columnIndex++ {
-			if isSymbolChar(bytemap.Bytes[coords.X+1][columnIndex]) {
-				return true
+	for x, row := range bytemap.Bytes {
+		for y, char := range row {
+			if isSymbolChar(char) {
+				index[Coords{X: x, Y: y}] = char
 			}
 		}
 	}
 
-	return false
-
-}
-
-func findStartOffset(bytemap *Bytemap, coords Number) int {
-	if coords.Y == 0 {
-		return 0
-	}
-	return coords.Y - 1
-}
-
-func findEndOffset(bytemap *Bytemap, coords Number) int {
-	if coords.Y+coords.Len == bytemap.RowSize {
-		return coords.Y + coords.Len
-	}
-	return coords.Y + coords.Len + 1
+	return index
 }
 
-func FindAllAsterisks(bytemap *Bytemap) Asterisks {
-	result := make(Asterisks)
+// AdjacentSymbols returns all the symbols around the number, including the diagonal ones,
+// row by row from the top left
+func (index SymbolIndex) AdjacentSymbols(coords Number) []Symbol {
+	var symbols []Symbol
 
-	for x := 0; x < bytemap.ColSize; x++ {
-		for y := 0; y < bytemap.RowSize; y++ {
-			if bytemap.Bytes[x][y] == 42 {
-				result[Coords{X: x, Y: y}] = []int{}
+	for x := coords.X - 1; x <= coords.X+1; x++ {
+		for y := coords.Y - 1; y <= coords.Y+coords.Len; y++ {
+			// the digits of the number itself are never symbols
+			if char, ok := index[Coords{X: x, Y: y}]; ok {
+				symbols = append(symbols, Symbol{Coords{X: x, Y: y}, char})
 			}
 		}
 	}
 
-	return result
+	return symbols
 }
 
 func FindAllNumbers(bytemap *Bytemap) *[]Number {
@@ -236,8 +278,101 @@ func charToDigit(char byte) int {
 	return int(char) - 48
 }
 
-func isAsterisk(char byte) bool {
-	return int(char) == 42
+// GraphNode is a number or a symbol of the schematic, its ID is unique and stable, e.g. "n3_5"
+// for the number starting in row 3, column 5
+type GraphNode struct {
+	Id     string `json:"id"`
+	Kind   string `json:"kind"`
+	Row    int    `json:"row"`
+	Column int    `json:"column"`
+	Text   string `json:"text"`
+}
+
+type GraphEdge struct {
+	Number string `json:"number"`
+	Symbol string `json:"symbol"`
+}
+
+// Graph is the adjacency of the numbers and the symbols of the schematic
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+func BuildGraph(bytemap *Bytemap) *Graph {
+	index := IndexSymbols(bytemap)
+	graph := &Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+
+	// the symbols first, in the reading order
+	for x, row := range bytemap.Bytes {
+		for y, char := range row {
+			if isSymbolChar(char) {
+				graph.Nodes = append(graph.Nodes, GraphNode{symbolId(Coords{X: x, Y: y}), "symbol", x, y, string(char)})
+			}
+		}
+	}
+
+	for _, coords := range *FindAllNumbers(bytemap) {
+		numberId := fmt.Sprintf("n%d_%d", coords.X, coords.Y)
+		graph.Nodes = append(graph.Nodes, GraphNode{numberId, "number", coords.X, coords.Y, strconv.Itoa(coords.Val)})
+
+		for _, symbol := range index.AdjacentSymbols(coords) {
+			graph.Edges = append(graph.Edges, GraphEdge{numberId, symbolId(symbol.Coords)})
+		}
+	}
+
+	return graph
+}
+
+func symbolId(coords Coords) string {
+	return fmt.Sprintf("s%d_%d", coords.X, coords.Y)
+}
+
+func (graph *Graph) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(graph)
+}
+
+// WriteDOT writes the graph for Graphviz, numbers are boxes and symbols are circles
+func (graph *Graph) WriteDOT(writer io.Writer) error {
+	buffered := bufio.NewWriter(writer)
+
+	fmt.Fprintln(buffered, "graph schematic {")
+	for _, node := range graph.Nodes {
+		shape := "box"
+		if node.Kind == "symbol" {
+			shape = "circle"
+		}
+		fmt.Fprintf(buffered, "  %s [label=%q, shape=%s];\n", node.Id, node.Text, shape)
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(buffered, "  %s -- %s;\n", edge.Number, edge.Symbol)
+	}
+	fmt.Fprintln(buffered, "}")
+
+	return buffered.Flush()
+}
+
+func Parse(reader io.Reader) (*Bytemap, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return linesToBytemap(lines), nil
+}
+
+func Format(writer io.Writer, bytemap *Bytemap) error {
+	buffered := bufio.NewWriter(writer)
+	for _, row := range bytemap.Bytes {
+		buffered.Write(row)
+		buffered.WriteByte('\n')
+	}
+	return buffered.Flush()
+}
+
+func Validate(lines []string) []error {
+	return input.CheckGrid(lines, "", 1)
 }
 
 func readBytemap(path string) *Bytemap {
@@ -248,21 +383,31 @@ func readBytemap(path string) *Bytemap {
 	}
 	defer fd.Close()
 
-	var result [][]byte
-	var rowSize int
+	var lines []string
 
 	scanner := bufio.NewScanner(fd)
 	for scanner.Scan() {
-		row := []byte(scanner.Text())
+		lines = append(lines, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		panic(err)
+	}
+	return linesToBytemap(lines)
+}
+
+func linesToBytemap(lines []string) *Bytemap {
+	var result [][]byte
+	var rowSize int
+
+	for _, line := range lines {
+		row := []byte(line)
 		if len(row) > rowSize {
 			rowSize = len(row)
 		}
 		result = append(result, row)
 	}
 
-	if err := scanner.Err(); err != nil {
-		panic(err)
-	}
 	return &Bytemap{
 		Bytes:   result,
 		RowSize: rowSize,
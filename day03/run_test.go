@@ -0,0 +1,155 @@
+package day03
+
+import (
+	"aoc2023/sampletest"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parseBytemap(t *testing.T, text string) *Bytemap {
+	t.Helper()
+	bytemap, err := Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytemap
+}
+
+func TestParseFormatRoundTrip(t *testing.T) {
+	sampletest.RoundTrip(t, Samples, Parse, Format, Part1, Part2)
+}
+
+func TestSumOfGearRatios(t *testing.T) {
+	tests := []struct {
+		schematic string
+		sum       int
+	}{
+		// the 2 is a part of both gears
+		{"1*2*3", 1*2 + 2*3},
+		{"1.\n*2\n3.", 0},
+		{"1..\n.*.\n..2", 2},
+		{"10*\n..5\n*..", 50},
+	}
+
+	for _, test := range tests {
+		if sum := SumOfGearRatios(parseBytemap(t, test.schematic)); sum != test.sum {
+			t.Errorf("%q: sum of gear ratios %d, expected %d", test.schematic, sum, test.sum)
+		}
+	}
+}
+
+func TestParseGearRule(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected GearRule
+		err      string
+	}{
+		{"*=2:product", DEFAULT_GEAR_RULE, ""},
+		{"#>=3:sum", GearRule{'#', 3, true, "sum"}, ""},
+		{"$=1:max", GearRule{'$', 1, false, "max"}, ""},
+		{"", GearRule{}, "expected a symbol first"},
+		{".=2:sum", GearRule{}, "expected a symbol first"},
+		{"*2:sum", GearRule{}, "expected = or >= after the symbol"},
+		{"*=2", GearRule{}, "expected :<aggregate> after the count"},
+		{"*=0:sum", GearRule{}, "the count must be a positive number"},
+		{"*>=x:sum", GearRule{}, "the count must be a positive number"},
+		{"*=2:mean", GearRule{}, "unknown aggregate mean"},
+	}
+
+	for _, test := range tests {
+		rule, err := ParseGearRule(test.rule)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%q: %v", test.rule, err)
+		case test.err == "" && rule != test.expected:
+			t.Errorf("%q: rule %+v, expected %+v", test.rule, rule, test.expected)
+		case test.err == "" && rule.String() != test.rule:
+			t.Errorf("%q: written back as %q", test.rule, rule.String())
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%q: error %v, expected %q", test.rule, err, test.err)
+		}
+	}
+}
+
+func TestSumOfGears(t *testing.T) {
+	// the # has 3 adjacent numbers, the * has 2
+	schematic := "4.5..\n.#...\n6..7*\n....8"
+	tests := []struct {
+		rule string
+		sum  int
+	}{
+		{"*=2:product", 7 * 8},
+		{"*=2:sum", 7 + 8},
+		{"*=2:max", 8},
+		{"*=3:sum", 0},
+		{"#=3:product", 4 * 5 * 6},
+		{"#>=3:sum", 4 + 5 + 6},
+		{"#>=4:sum", 0},
+		{"#>=2:max", 6},
+		{"*>=1:sum", 7 + 8},
+	}
+
+	bytemap := parseBytemap(t, schematic)
+	for _, test := range tests {
+		rule, err := ParseGearRule(test.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sum := SumOfGears(bytemap, rule); sum != test.sum {
+			t.Errorf("%s: sum %d, expected %d", test.rule, sum, test.sum)
+		}
+	}
+}
+
+func TestBuildGraph(t *testing.T) {
+	graph := BuildGraph(parseBytemap(t, "1*2\n...\n#.3"))
+
+	expected := &Graph{
+		Nodes: []GraphNode{
+			{"s0_1", "symbol", 0, 1, "*"},
+			{"s2_0", "symbol", 2, 0, "#"},
+			{"n0_0", "number", 0, 0, "1"},
+			{"n0_2", "number", 0, 2, "2"},
+			{"n2_2", "number", 2, 2, "3"},
+		},
+		Edges: []GraphEdge{{"n0_0", "s0_1"}, {"n0_2", "s0_1"}},
+	}
+	if !reflect.DeepEqual(graph, expected) {
+		t.Fatalf("graph %+v, expected %+v", graph, expected)
+	}
+
+	jsonOutput := &strings.Builder{}
+	if err := graph.WriteJSON(jsonOutput); err != nil {
+		t.Fatal(err)
+	}
+	decoded := &Graph{}
+	if err := json.Unmarshal([]byte(jsonOutput.String()), decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, jsonOutput)
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("JSON decodes to %+v, expected %+v", decoded, expected)
+	}
+	if !strings.Contains(jsonOutput.String(), `"id": "n0_2",`) {
+		t.Errorf("JSON without the indented number id:\n%s", jsonOutput)
+	}
+
+	dotOutput := &strings.Builder{}
+	if err := graph.WriteDOT(dotOutput); err != nil {
+		t.Fatal(err)
+	}
+	expectedDot := `graph schematic {
+  s0_1 [label="*", shape=circle];
+  s2_0 [label="#", shape=circle];
+  n0_0 [label="1", shape=box];
+  n0_2 [label="2", shape=box];
+  n2_2 [label="3", shape=box];
+  n0_0 -- s0_1;
+  n0_2 -- s0_1;
+}
+`
+	if dotOutput.String() != expectedDot {
+		t.Errorf("DOT output:\n%s\nexpected:\n%s", dotOutput, expectedDot)
+	}
+}
@@ -1,17 +1,25 @@
 package day04
 
 import (
+	"aoc2023/input"
+	"aoc2023/trace"
 	"bufio"
+	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
+const TITLE = "Scratchcards"
 const INPUT_FILE_PATH = "day04/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 type Card struct {
 	Id      int
 	Winning []int
@@ -20,88 +28,216 @@ type Card struct {
 
 func Run() {
 	inputLines := readLines(INPUT_FILE_PATH)
-	cards := parseInputLines(inputLines)
-	cardCopyCount := make(map[int]int)
 
-	totalScore := 0
-	totalCards := 0
+	totalScore, err := Part1(inputLines)
+	if err != nil {
+		panic(err)
+	}
+
+	totalCards, err := Part2(inputLines)
+	if err != nil {
+		panic(err)
+	}
 
+	fmt.Println("Total score [PART 1]: ", totalScore)
+	fmt.Println("Total cards [PART 2]: ", totalCards)
+}
+
+func Part1(inputLines []string) (int, error) {
+	cards, err := parseCards(inputLines)
+	if err != nil {
+		return 0, err
+	}
+
+	totalScore := 0
 	for _, card := range cards {
-		cardCopyCount[card.Id] += 1
-		score := calculateScore(card)
-		intersectionSize := intersectionSize(card)
+		totalScore += score(Matches(card))
+	}
+
+	return totalScore, nil
+}
+
+func Part2(inputLines []string) (int, error) {
+	cards, err := parseCards(inputLines)
+	if err != nil {
+		return 0, err
+	}
+
+	return NewScratchcards(cards).TotalCards(), nil
+}
+
+// Contribution is a number of copies of a card won by an earlier card, the cards are given by their IDs
+type Contribution struct {
+	From   int
+	To     int
+	Copies int
+}
+
+// Scratchcards is the played out copy cascade, a card wins a copy of the next cards in the list
+// for every one of its instances, as many cards as it has matching numbers
+type Scratchcards struct {
+	Cards []*Card
+	// by the position of the card in the list
+	Matches []int
+	Copies  []int
+	// in the order the copies are won
+	Contributions []Contribution
+}
 
-		totalScore += score
-		for i := 1; i <= intersectionSize; i++ {
-			cardCopyCount[card.Id + i] += cardCopyCount[card.Id]
+func NewScratchcards(cards []*Card) *Scratchcards {
+	scratchcards := &Scratchcards{
+		Cards:   cards,
+		Matches: make([]int, len(cards)),
+		Copies:  make([]int, len(cards)),
+	}
+
+	for i, card := range cards {
+		scratchcards.Matches[i] = Matches(card)
+		scratchcards.Copies[i] += 1
+
+		for j := i + 1; j <= i+scratchcards.Matches[i] && j < len(cards); j++ {
+			scratchcards.Copies[j] += scratchcards.Copies[i]
+			scratchcards.Contributions = append(scratchcards.Contributions, Contribution{card.Id, cards[j].Id, scratchcards.Copies[i]})
 		}
 	}
 
-	for _, copyCount := range cardCopyCount {
-		totalCards += copyCount
+	return scratchcards
+}
+
+func (scratchcards *Scratchcards) TotalCards() int {
+	totalCards := 0
+	for _, copies := range scratchcards.Copies {
+		totalCards += copies
 	}
+	return totalCards
+}
 
-	fmt.Println("Total score [PART 1]: ", totalScore)
-	fmt.Println("Total cards [PART 2]: ", totalCards)
+// ContributionsTo returns the copies of the card won from the earlier cards
+func (scratchcards *Scratchcards) ContributionsTo(cardId int) []Contribution {
+	var contributions []Contribution
+	for _, contribution := range scratchcards.Contributions {
+		if contribution.To == cardId {
+			contributions = append(contributions, contribution)
+		}
+	}
+	return contributions
 }
 
-func intersectionSize(card *Card) int {
-	intersection := make(map[int]int)
-	size := 0
+// ContributionsFrom returns the copies of the later cards won by the card
+func (scratchcards *Scratchcards) ContributionsFrom(cardId int) []Contribution {
+	var contributions []Contribution
+	for _, contribution := range scratchcards.Contributions {
+		if contribution.From == cardId {
+			contributions = append(contributions, contribution)
+		}
+	}
+	return contributions
+}
 
-	for _, number := range card.Present {
-		intersection[number] += 1
+// Explain traces the matching numbers, the score and the copy cascade of the card on the given line (from 1):
+// the copies it is won from the earlier cards and the copies it wins of the later ones
+func Explain(inputLines []string, line int, tracer *trace.Tracer) error {
+	cards, err := parseCards(inputLines)
+	if err != nil {
+		return err
 	}
-	for _, number := range card.Winning {
-		intersection[number] += 1
+	if line < 1 || line > len(cards) {
+		return fmt.Errorf("no card on line %d, there are %d cards", line, len(cards))
 	}
 
-	for _, occurrence := range intersection {
-		if occurrence == 2 {
-			size += 1
-		}
+	card := cards[line-1]
+	matchingNumbers := MatchingNumbers(card)
+	tracer.Emit("card", trace.Fields{"card": card.Id, "matches": matchingNumbers, "score": score(len(matchingNumbers))})
+
+	scratchcards := NewScratchcards(cards)
+	for _, contribution := range scratchcards.ContributionsTo(card.Id) {
+		tracer.Emit("won", trace.Fields{"card": card.Id, "from": contribution.From, "copies": contribution.Copies})
+	}
+	tracer.Emit("instances", trace.Fields{"card": card.Id, "copies": scratchcards.Copies[line-1]})
+	for _, contribution := range scratchcards.ContributionsFrom(card.Id) {
+		tracer.Emit("wins", trace.Fields{"card": contribution.To, "copies": contribution.Copies})
 	}
+	return nil
+}
 
-	return size
+// Matches counts the present numbers that are winning, a number present twice counts once
+func Matches(card *Card) int {
+	return len(MatchingNumbers(card))
 }
 
-func calculateScore(card *Card) int {
-	occurrences := make(map[int]int)
-	score := 0
+// MatchingNumbers lists the present numbers that are winning, a number present twice is listed once
+func MatchingNumbers(card *Card) []int {
+	winning := make(map[int]bool, len(card.Winning))
+	for _, number := range card.Winning {
+		winning[number] = true
+	}
 
+	matches := []int{}
 	for _, number := range card.Present {
-		occurrences[number] += 1
+		if winning[number] {
+			matches = append(matches, number)
+			winning[number] = false
+		}
 	}
-	for _, number := range card.Winning {
-		occurrences[number] += 1
+
+	return matches
+}
+
+// score is 1 for the first match and doubles for every next one
+func score(matches int) int {
+	if matches == 0 {
+		return 0
 	}
+	return 1 << (matches - 1)
+}
+
+// StreamScratchcards scores and plays out the cards read line by line, only the copies won for the
+// next cards are kept, so the memory depends on the most matches of a card, not on the number of cards
+func StreamScratchcards(reader io.Reader) (totalScore int, totalCards int, err error) {
+	scanner := bufio.NewScanner(reader)
+	// the copies won for the next cards, the first one is for the current card
+	window := []int{}
 
-	for _, occurrence := range occurrences {
-		if occurrence == 2 {
-			if score == 0 {
-				score = 1
-			} else {
-				score *= 2
-			}
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		card, err := parseCardLine(scanner.Text())
+		if err != nil {
+			return 0, 0, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		copies := 1
+		if len(window) > 0 {
+			copies += window[0]
+			window = window[1:]
+		}
+
+		matches := Matches(card)
+		for len(window) < matches {
+			window = append(window, 0)
 		}
+		for i := 0; i < matches; i++ {
+			window[i] += copies
+		}
+
+		totalScore += score(matches)
+		totalCards += copies
 	}
 
-	return score
+	return totalScore, totalCards, scanner.Err()
 }
 
-func parseInputLines(inputLines []string) []*Card {
+func parseCards(inputLines []string) ([]*Card, error) {
 	var cards []*Card
 
 	for _, inputLine := range inputLines {
 		card, err := parseCardLine(inputLine)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 
 		cards = append(cards, card)
 	}
 
-	return cards
+	return cards, nil
 }
 
 func parseCardLine(line string) (*Card, error) {
@@ -144,6 +280,33 @@ func parseCardLine(line string) (*Card, error) {
 	return &card, nil
 }
 
+func Parse(reader io.Reader) ([]*Card, error) {
+	inputLines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseCards(inputLines)
+}
+
+// Format writes the cards with the numbers aligned in columns, like the puzzle input
+func Format(writer io.Writer, cards []*Card) error {
+	buffered := bufio.NewWriter(writer)
+
+	for _, card := range cards {
+		fmt.Fprintf(buffered, "Card %3d: %s | %s\n", card.Id, numbersString(card.Winning), numbersString(card.Present))
+	}
+
+	return buffered.Flush()
+}
+
+func numbersString(numbers []int) string {
+	numberStrings := make([]string, len(numbers))
+	for i, number := range numbers {
+		numberStrings[i] = fmt.Sprintf("%2d", number)
+	}
+	return strings.Join(numberStrings, " ")
+}
+
 func readLines(path string) []string {
 	// Read input file (line by line) from input.txt
 	fd, err := os.Open(path)
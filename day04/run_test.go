@@ -0,0 +1,52 @@
+package day04
+
+import (
+	"aoc2023/input"
+	"aoc2023/sampletest"
+	"strings"
+	"testing"
+)
+
+func TestParseFormatRoundTrip(t *testing.T) {
+	sampletest.RoundTrip(t, Samples, Parse, Format, Part1, Part2)
+}
+
+func TestStreamScratchcards(t *testing.T) {
+	for _, sample := range sampletest.Load(t, Samples) {
+		lines, err := input.Lines(strings.NewReader(sample.Input))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		score, totalCards, err := StreamScratchcards(strings.NewReader(sample.Input))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		part1, err1 := Part1(lines)
+		part2, err2 := Part2(lines)
+		if err1 != nil || err2 != nil {
+			t.Fatalf("%s: %v, %v", sample.Name, err1, err2)
+		}
+		if score != part1 || totalCards != part2 {
+			t.Errorf("%s: score %d and %d cards streamed, the parts give %d and %d",
+				sample.Name, score, totalCards, part1, part2)
+		}
+		if score != sample.Answers[1] || totalCards != sample.Answers[2] {
+			t.Errorf("%s: score %d and %d cards, expected %d and %d",
+				sample.Name, score, totalCards, sample.Answers[1], sample.Answers[2])
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct{ matches, score int }{
+		{0, 0}, {1, 1}, {2, 2}, {4, 8}, {10, 512},
+	}
+
+	for _, test := range tests {
+		if result := score(test.matches); result != test.score {
+			t.Errorf("%d matches: score %d, expected %d", test.matches, result, test.score)
+		}
+	}
+}
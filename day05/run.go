@@ -1,11 +1,17 @@
 package day05
 
 import (
+	"aoc2023/input"
 	"bufio"
+	"embed"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -77,6 +83,45 @@ func Intersection(r1 Range, r2 Range) (bool, Range) {
 	return true, Range{Start: r2.Start, Length: r2.Length}
 }
 
+// Normalize sorts the ranges and merges the overlapping and adjacent ones
+func (ranges Ranges) Normalize() Ranges {
+	sorted := slices.Clone(ranges)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	normalized := Ranges{}
+	for _, range_ := range sorted {
+		if range_.Length <= 0 {
+			continue
+		}
+
+		last := len(normalized) - 1
+		if last >= 0 && range_.Start <= normalized[last].Start+normalized[last].Length {
+			end := max(normalized[last].Start+normalized[last].Length, range_.Start+range_.Length)
+			normalized[last].Length = end - normalized[last].Start
+			continue
+		}
+		normalized = append(normalized, range_)
+	}
+	return normalized
+}
+
+// Without removes the range from all the ranges
+func (ranges Ranges) Without(cut Range) Ranges {
+	result := Ranges{}
+	for _, range_ := range ranges {
+		ok, intersection := Intersection(range_, cut)
+		if !ok {
+			result = append(result, range_)
+			continue
+		}
+		// the intersection is within the range, which is what Subtract expects
+		result = append(result, range_.Subtract(intersection)...)
+	}
+	return result
+}
+
 type Mapping struct {
 	Diff        int
 	SourceRange Range
@@ -88,129 +133,337 @@ type CategoryMap struct {
 	Mappings     []Mapping
 }
 
+// Almanac keeps the maps by their source category, there may be more than one when the maps branch
 type Almanac struct {
-	CategoryMaps map[string]*CategoryMap
+	Seeds        []int
+	CategoryMaps map[string][]*CategoryMap
 }
 
+const SEED_CATEGORY = "seed"
+const LOCATION_CATEGORY = "location"
+
+var ErrNoRoute = errors.New("no route")
+
+// Route is a chain of maps, every map converts to the source category of the next one
+type Route []*CategoryMap
+
+const TITLE = "If You Give A Seed A Fertilizer"
 const INPUT_FILE_PATH = "day05/input_test.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 func Run() {
 	inputLines := readLines(INPUT_FILE_PATH)
-	almanac, err := parseInput(inputLines)
-	if err != nil {
-		panic(err)
-	}
-
-	seeds, err := parseSeedsAsSingleNumbers(inputLines[0])
-	if err != nil {
-		panic(err)
-	}
-
-	seedRanges, err := parseSeedsAsRanges(inputLines[0])
-	if err != nil {
-		panic(err)
-	}
 
 	var startTime time.Time
 	var elapsedTime time.Duration
 
 	startTime = time.Now()
-	result1 := almanac.MinimalLocationFromSeeds(seeds)
+	result1, err := Part1(inputLines)
+	if err != nil {
+		panic(err)
+	}
 	elapsedTime = time.Since(startTime)
 
 	fmt.Println("Minimal converted seed [PART 1]: ", result1, ";\tElapsed time: ", elapsedTime)
 
 	startTime = time.Now()
-	result2 := almanac.OptimalMinimalLocationFromSeedRanges(seedRanges)
+	result2, err := Part2(inputLines)
+	if err != nil {
+		panic(err)
+	}
 	elapsedTime = time.Since(startTime)
 
 	fmt.Println("Minimal converted seed [PART 2]: ", result2, ";\tElapsed time: ", elapsedTime)
 }
 
-func (almanac *Almanac) MinimalLocationFromSeeds(seeds []int) int {
-	var locations []int
-	var minLocation int
+func Part1(inputLines []string) (int, error) {
+	almanac, err := parseInput(inputLines)
+	if err != nil {
+		return 0, err
+	}
 
-	for _, seed := range seeds {
-		locations = append(locations, almanac.ConvertSeedFromInt(seed))
+	return almanac.MinimalLocationFromSeeds(almanac.Seeds)
+}
+
+func Part2(inputLines []string) (int, error) {
+	almanac, err := parseInput(inputLines)
+	if err != nil {
+		return 0, err
 	}
-	minLocation = math.MaxInt
 
-	for _, seed := range locations {
-		if seed < minLocation {
-			minLocation = seed
-		}
+	seedRanges, err := parseSeedsAsRanges(inputLines[0])
+	if err != nil {
+		return 0, err
 	}
 
-	return minLocation
+	return almanac.OptimalMinimalLocationFromSeedRanges(seedRanges)
 }
 
-func (almanac *Almanac) BruteForceMinimalLocationFromSeedRanges(seedRanges []Range) int {
+func (almanac *Almanac) MinimalLocationFromSeeds(seeds []int) (int, error) {
+	route, err := almanac.Route(SEED_CATEGORY, LOCATION_CATEGORY)
+	if err != nil {
+		return 0, err
+	}
+
 	minLocation := math.MaxInt
+	for _, seed := range seeds {
+		minLocation = min(minLocation, route.ConvertInt(seed))
+	}
 
+	return minLocation, nil
+}
+
+func (almanac *Almanac) BruteForceMinimalLocationFromSeedRanges(seedRanges []Range) (int, error) {
+	route, err := almanac.Route(SEED_CATEGORY, LOCATION_CATEGORY)
+	if err != nil {
+		return 0, err
+	}
+
+	minLocation := math.MaxInt
 	for _, seedRange := range seedRanges {
 		for i := 0; i < seedRange.Length; i++ {
-			seed := almanac.ConvertSeedFromInt(seedRange.Start + i)
-			if seed < minLocation {
-				minLocation = seed
-			}
+			minLocation = min(minLocation, route.ConvertInt(seedRange.Start+i))
 		}
 	}
 
-	return minLocation
+	return minLocation, nil
 }
 
-func (almanac *Almanac) OptimalMinimalLocationFromSeedRanges(seedRanges []Range) int {
-	var ranges Ranges
-
-	for _, seedRange := range seedRanges {
-		newRanges := almanac.ConvertSeedFromRange(seedRange)
-		ranges = append(ranges, newRanges...)
+func (almanac *Almanac) OptimalMinimalLocationFromSeedRanges(seedRanges []Range) (int, error) {
+	route, err := almanac.Route(SEED_CATEGORY, LOCATION_CATEGORY)
+	if err != nil {
+		return 0, err
 	}
 
 	minRangeStart := math.MaxInt
-	for _, range_ := range ranges {
+	for _, range_ := range route.ConvertRanges(seedRanges) {
 		if range_.Start < minRangeStart {
 			minRangeStart = range_.Start
 		}
 	}
-	return minRangeStart
+	return minRangeStart, nil
+}
+
+// Inverse returns all the numbers of the from category that convert to the number of the to category
+func (almanac *Almanac) Inverse(fromCategory string, toCategory string, number int) ([]int, error) {
+	route, err := almanac.Route(fromCategory, toCategory)
+	if err != nil {
+		return nil, err
+	}
+	return route.InverseInt(number), nil
+}
+
+// InverseRanges returns the ranges of the from category that convert into the ranges of the to category,
+// e.g. the seed ranges that land in a location range
+func (almanac *Almanac) InverseRanges(fromCategory string, toCategory string, ranges []Range) ([]Range, error) {
+	route, err := almanac.Route(fromCategory, toCategory)
+	if err != nil {
+		return nil, err
+	}
+	return route.InverseRanges(ranges), nil
+}
 
+// Convert converts a number between any two categories, e.g. from soil to humidity
+func (almanac *Almanac) Convert(fromCategory string, toCategory string, number int) (int, error) {
+	route, err := almanac.Route(fromCategory, toCategory)
+	if err != nil {
+		return 0, err
+	}
+	return route.ConvertInt(number), nil
 }
 
-func (almanac *Almanac) ConvertSeedFromInt(initNumber int) int {
-	category := "seed"
-	number := initNumber
+func (almanac *Almanac) ConvertRanges(fromCategory string, toCategory string, ranges []Range) ([]Range, error) {
+	route, err := almanac.Route(fromCategory, toCategory)
+	if err != nil {
+		return nil, err
+	}
+	return route.ConvertRanges(ranges), nil
+}
 
-	for {
-		categoryMap := almanac.CategoryMaps[category]
-		if categoryMap == nil {
-			return number
+// Route finds the shortest chain of maps between the categories (breadth first), a category
+// that no map converts from is a dead end and cycles of the maps are never followed twice
+func (almanac *Almanac) Route(fromCategory string, toCategory string) (Route, error) {
+	categories := almanac.Categories()
+	for _, category := range []string{fromCategory, toCategory} {
+		if !slices.Contains(categories, category) {
+			return nil, fmt.Errorf("unknown category %s, the almanac has: %s", category, strings.Join(categories, ", "))
 		}
+	}
 
-		category = categoryMap.ToCategory
-		number = categoryMap.ConvertInt(number)
+	// the map each category was first reached with
+	reachedWith := map[string]*CategoryMap{fromCategory: nil}
+	queue := []string{fromCategory}
+	var deadEnds []string
+
+	for len(queue) > 0 && reachedWith[toCategory] == nil && fromCategory != toCategory {
+		category := queue[0]
+		queue = queue[1:]
+
+		if len(almanac.CategoryMaps[category]) == 0 {
+			deadEnds = append(deadEnds, category)
+		}
+		for _, categoryMap := range almanac.CategoryMaps[category] {
+			if _, reached := reachedWith[categoryMap.ToCategory]; !reached {
+				reachedWith[categoryMap.ToCategory] = categoryMap
+				queue = append(queue, categoryMap.ToCategory)
+			}
+		}
 	}
+
+	if _, reached := reachedWith[toCategory]; !reached {
+		return nil, fmt.Errorf("%w from %s to %s, the maps lead to the dead ends: %s",
+			ErrNoRoute, fromCategory, toCategory, strings.Join(deadEnds, ", "))
+	}
+
+	route := Route{}
+	for category := toCategory; category != fromCategory; {
+		categoryMap := reachedWith[category]
+		route = append(Route{categoryMap}, route...)
+		category = categoryMap.FromCategory
+	}
+	return route, nil
+}
+
+// Categories lists all the categories the maps convert from or to, sorted
+func (almanac *Almanac) Categories() []string {
+	categories := []string{}
+	for category, categoryMaps := range almanac.CategoryMaps {
+		categories = append(categories, category)
+		for _, categoryMap := range categoryMaps {
+			categories = append(categories, categoryMap.ToCategory)
+		}
+	}
+
+	sort.Strings(categories)
+	return slices.Compact(categories)
 }
 
-func (almanac *Almanac) ConvertSeedFromRange(initRange Range) []Range {
-	category := "seed"
-	result := []Range{initRange}
+// Cycles returns the cycles of the maps, each as the categories along it starting and
+// ending with the same category, e.g. [soil water soil]
+func (almanac *Almanac) Cycles() [][]string {
+	const (
+		UNVISITED = iota
+		IN_PROGRESS
+		DONE
+	)
+	states := make(map[string]int)
+	var path []string
+	var cycles [][]string
+
+	var visit func(category string)
+	visit = func(category string) {
+		states[category] = IN_PROGRESS
+		path = append(path, category)
+
+		for _, categoryMap := range almanac.CategoryMaps[category] {
+			switch states[categoryMap.ToCategory] {
+			case UNVISITED:
+				visit(categoryMap.ToCategory)
+			case IN_PROGRESS:
+				start := slices.Index(path, categoryMap.ToCategory)
+				cycle := append(slices.Clone(path[start:]), categoryMap.ToCategory)
+				cycles = append(cycles, cycle)
+			}
+		}
+
+		path = path[:len(path)-1]
+		states[category] = DONE
+	}
 
-	for {
-		categoryMap := almanac.CategoryMaps[category]
-		if categoryMap == nil {
-			return result
+	for _, category := range almanac.Categories() {
+		if states[category] == UNVISITED {
+			visit(category)
 		}
+	}
+	return cycles
+}
+
+func (route Route) ConvertInt(number int) int {
+	for _, categoryMap := range route {
+		number = categoryMap.ConvertInt(number)
+	}
+	return number
+}
 
+func (route Route) ConvertRanges(ranges []Range) []Range {
+	result := slices.Clone(ranges)
+
+	for _, categoryMap := range route {
 		var newRanges []Range
-		category = categoryMap.ToCategory
 		for _, range_ := range result {
 			convertedRanges := categoryMap.ConvertRange(range_)
 			newRanges = append(newRanges, convertedRanges...)
 		}
 		result = newRanges
 	}
+	return result
+}
+
+// InverseInt returns all the numbers the route converts to the number, e.g. the seeds of a location
+func (route Route) InverseInt(number int) []int {
+	numbers := []int{number}
+
+	for i := len(route) - 1; i >= 0; i-- {
+		var sources []int
+		for _, dest := range numbers {
+			sources = append(sources, route[i].InverseInt(dest)...)
+		}
+		sort.Ints(sources)
+		numbers = slices.Compact(sources)
+	}
+	return numbers
+}
+
+// InverseRanges returns the ranges of all the numbers the route converts into the ranges
+func (route Route) InverseRanges(ranges []Range) []Range {
+	result := Ranges(ranges).Normalize()
+
+	for i := len(route) - 1; i >= 0; i-- {
+		var sources Ranges
+		for _, range_ := range result {
+			sources = append(sources, route[i].InverseRange(range_)...)
+		}
+		result = sources.Normalize()
+	}
+	return result
+}
+
+// CheckInverse converts the inverse of the ranges forward again, which has to stay within the ranges,
+// the numbers of the ranges no number converts to are missing from it, which is fine
+func (route Route) CheckInverse(ranges []Range) error {
+	outside := Ranges(route.ConvertRanges(route.InverseRanges(ranges))).Normalize()
+	for _, range_ := range ranges {
+		outside = outside.Without(range_)
+	}
+	if len(outside) > 0 {
+		return fmt.Errorf("the inverse of %v along %s converts back outside of it: %v", ranges, route, outside)
+	}
+
+	for _, range_ := range ranges {
+		for _, number := range []int{range_.Start, range_.Start + range_.Length - 1} {
+			for _, source := range route.InverseInt(number) {
+				if dest := route.ConvertInt(source); dest != number {
+					return fmt.Errorf("the inverse %d of %d along %s converts back to %d", source, number, route, dest)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// String writes the categories along the route, e.g. "soil -> fertilizer -> water"
+func (route Route) String() string {
+	if len(route) == 0 {
+		return "(empty route)"
+	}
+
+	categories := []string{route[0].FromCategory}
+	for _, categoryMap := range route {
+		categories = append(categories, categoryMap.ToCategory)
+	}
+	return strings.Join(categories, " -> ")
 }
 
 func (categoryMap *CategoryMap) ConvertRange(_range Range) []Range {
@@ -251,6 +504,47 @@ func (categoryMap *CategoryMap) ConvertInt(source int) int {
 	return source
 }
 
+// InverseInt returns all the sources the map converts to the destination, there are none
+// when the destination is only reached from outside of it, or more when it is reached
+// both by a mapping and by a number no mapping converts
+func (categoryMap *CategoryMap) InverseInt(dest int) []int {
+	var sources []int
+	mapped := false
+
+	for _, mapping := range categoryMap.Mappings {
+		if ok, _ := mapping.Convert(dest - mapping.Diff); ok {
+			sources = append(sources, dest-mapping.Diff)
+		}
+		if ok, _ := mapping.Convert(dest); ok {
+			mapped = true
+		}
+	}
+
+	// a number no mapping converts stays the same
+	if !mapped {
+		sources = append(sources, dest)
+	}
+
+	sort.Ints(sources)
+	return slices.Compact(sources)
+}
+
+// InverseRange returns the ranges of all the sources the map converts into the destination range
+func (categoryMap *CategoryMap) InverseRange(destRange Range) []Range {
+	sources := Ranges{}
+	unmapped := Ranges{destRange}
+
+	for _, mapping := range categoryMap.Mappings {
+		mappedDest := Range{Start: mapping.SourceRange.Start + mapping.Diff, Length: mapping.SourceRange.Length}
+		if ok, intersection := Intersection(mappedDest, destRange); ok {
+			sources = append(sources, Range{Start: intersection.Start - mapping.Diff, Length: intersection.Length})
+		}
+		unmapped = unmapped.Without(mapping.SourceRange)
+	}
+
+	return append(sources, unmapped...).Normalize()
+}
+
 func (mapping *Mapping) Convert(source int) (bool, int) {
 	if source >= mapping.SourceRange.Start && source < mapping.SourceRange.Start+mapping.SourceRange.Length {
 		return true, source + mapping.Diff
@@ -260,7 +554,17 @@ func (mapping *Mapping) Convert(source int) (bool, int) {
 
 func parseInput(lines []string) (*Almanac, error) {
 	result := Almanac{}
-	result.CategoryMaps = make(map[string]*CategoryMap)
+	result.CategoryMaps = make(map[string][]*CategoryMap)
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("empty almanac")
+	}
+
+	seeds, err := parseSeedsAsSingleNumbers(lines[0])
+	if err != nil {
+		return nil, err
+	}
+	result.Seeds = seeds
 
 	for lineIndex := 1; lineIndex < len(lines); {
 		if lines[lineIndex] == "" {
@@ -274,10 +578,17 @@ func parseInput(lines []string) (*Almanac, error) {
 				return nil, err
 			}
 
-			result.CategoryMaps[categoryMap.FromCategory] = categoryMap
+			for _, otherMap := range result.CategoryMaps[categoryMap.FromCategory] {
+				if otherMap.ToCategory == categoryMap.ToCategory {
+					return nil, fmt.Errorf("second map from %s to %s", categoryMap.FromCategory, categoryMap.ToCategory)
+				}
+			}
+			result.CategoryMaps[categoryMap.FromCategory] = append(result.CategoryMaps[categoryMap.FromCategory], categoryMap)
 			lineIndex = newIndex + 1
 			continue
 		}
+
+		return nil, fmt.Errorf("invalid almanac line: %s", lines[lineIndex])
 	}
 
 	return &result, nil
@@ -376,6 +687,124 @@ func parseMap(lines *[]string, initIndex int) (*CategoryMap, int, error) {
 	return &result, index, nil
 }
 
+func Parse(reader io.Reader) (*Almanac, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(lines)
+}
+
+// Format writes the seeds and the maps in the order they are reached from the seed
+// category, breadth first, maps outside the reach come last
+func Format(writer io.Writer, almanac *Almanac) error {
+	buffered := bufio.NewWriter(writer)
+
+	seedStrings := make([]string, len(almanac.Seeds))
+	for i, seed := range almanac.Seeds {
+		seedStrings[i] = strconv.Itoa(seed)
+	}
+	fmt.Fprintf(buffered, "seeds: %s\n", strings.Join(seedStrings, " "))
+
+	for _, categoryMap := range almanac.orderedCategoryMaps() {
+		fmt.Fprintf(buffered, "\n%s-to-%s map:\n", categoryMap.FromCategory, categoryMap.ToCategory)
+		for _, mapping := range categoryMap.Mappings {
+			fmt.Fprintf(buffered, "%d %d %d\n",
+				mapping.SourceRange.Start+mapping.Diff, mapping.SourceRange.Start, mapping.SourceRange.Length)
+		}
+	}
+
+	return buffered.Flush()
+}
+
+func (almanac *Almanac) orderedCategoryMaps() []*CategoryMap {
+	var ordered []*CategoryMap
+	visited := map[string]bool{SEED_CATEGORY: true}
+	queue := []string{SEED_CATEGORY}
+
+	for len(queue) > 0 {
+		category := queue[0]
+		queue = queue[1:]
+
+		for _, categoryMap := range almanac.CategoryMaps[category] {
+			ordered = append(ordered, categoryMap)
+			if !visited[categoryMap.ToCategory] {
+				visited[categoryMap.ToCategory] = true
+				queue = append(queue, categoryMap.ToCategory)
+			}
+		}
+	}
+
+	for _, category := range almanac.Categories() {
+		if !visited[category] {
+			ordered = append(ordered, almanac.CategoryMaps[category]...)
+		}
+	}
+	return ordered
+}
+
+// Validate checks the seeds line and every map line, then that the maps have no cycles and
+// lead from the seed category to the location category, branches and dead ends are fine
+func Validate(lines []string) []error {
+	var problems []error
+	if len(lines) == 0 {
+		return []error{fmt.Errorf("empty almanac")}
+	}
+
+	seeds, err := parseSeedsAsSingleNumbers(lines[0])
+	if err != nil || !strings.HasPrefix(lines[0], "seeds: ") {
+		problems = append(problems, fmt.Errorf("line 1: invalid seeds line: %s", lines[0]))
+	} else if len(seeds)%2 != 0 {
+		problems = append(problems, fmt.Errorf("line 1: odd number of seeds, part 2 reads them as pairs"))
+	}
+
+	prefixRegex := regexp.MustCompile(`^(\w+)\-to\-(\w+)\s+map:\s*$`)
+	mappingRegex := regexp.MustCompile(`^\d+ \d+ \d+$`)
+	mapLines := make(map[[2]string]int)
+	inMap := false
+
+	for i, line := range lines[1:] {
+		lineNumber := i + 2
+
+		switch matches := prefixRegex.FindStringSubmatch(line); {
+		case line == "":
+			inMap = false
+		case matches != nil:
+			categories := [2]string{matches[1], matches[2]}
+			if firstLine, ok := mapLines[categories]; ok {
+				problems = append(problems, fmt.Errorf("line %d: second map from %s to %s, the first is on line %d",
+					lineNumber, matches[1], matches[2], firstLine))
+			} else {
+				mapLines[categories] = lineNumber
+			}
+			inMap = true
+		case !inMap:
+			problems = append(problems, fmt.Errorf("line %d: expected a map header: %s", lineNumber, line))
+		case !mappingRegex.MatchString(line):
+			problems = append(problems, fmt.Errorf("line %d: expected three numbers: %s", lineNumber, line))
+		}
+	}
+
+	// the routing needs the parsed almanac, which the problems above would stop
+	if len(problems) > 0 {
+		return problems
+	}
+	almanac, err := parseInput(lines)
+	if err != nil {
+		return []error{err}
+	}
+
+	for _, cycle := range almanac.Cycles() {
+		problems = append(problems, fmt.Errorf("the maps form a cycle: %s", strings.Join(cycle, " -> ")))
+	}
+
+	// the maps off the route are fine, the convert command can still use them
+	if _, err := almanac.Route(SEED_CATEGORY, LOCATION_CATEGORY); err != nil {
+		problems = append(problems, err)
+	}
+	return problems
+}
+
 func readLines(path string) []string {
 	// Read input file (line by line) from input.txt
 	fd, err := os.Open(path)
@@ -0,0 +1,226 @@
+package day05
+
+import (
+	"aoc2023/sampletest"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// the seed category branches, the route through soil is the shortest one
+const BRANCHING_ALMANAC = `seeds: 1
+
+seed-to-soil map:
+50 0 10
+
+seed-to-fertilizer map:
+100 0 1
+
+soil-to-location map:
+0 50 5
+
+fertilizer-to-water map:
+0 0 1
+
+water-to-location map:
+0 0 1
+
+soil-to-dead map:
+0 0 1
+`
+
+// the location is only reached from light, which nothing converts to
+const DEAD_END_ALMANAC = `seeds: 1
+
+seed-to-soil map:
+0 0 1
+
+soil-to-water map:
+0 0 1
+
+water-to-soil map:
+0 0 1
+
+light-to-location map:
+0 0 1
+`
+
+func parseAlmanac(t *testing.T, text string) *Almanac {
+	t.Helper()
+	almanac, err := Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return almanac
+}
+
+func sampleAlmanac(t *testing.T) *Almanac {
+	t.Helper()
+	return parseAlmanac(t, sampletest.Load(t, Samples)[0].Input)
+}
+
+func TestParseFormatRoundTrip(t *testing.T) {
+	sampletest.RoundTrip(t, Samples, Parse, Format, Part1, Part2)
+}
+
+func TestRoute(t *testing.T) {
+	tests := []struct {
+		almanac string
+		from    string
+		to      string
+		route   string
+		err     string
+	}{
+		{BRANCHING_ALMANAC, SEED_CATEGORY, LOCATION_CATEGORY, "seed -> soil -> location", ""},
+		{BRANCHING_ALMANAC, "fertilizer", LOCATION_CATEGORY, "fertilizer -> water -> location", ""},
+		{BRANCHING_ALMANAC, "soil", "dead", "soil -> dead", ""},
+		{BRANCHING_ALMANAC, SEED_CATEGORY, "moon", "", "unknown category moon"},
+		{BRANCHING_ALMANAC, "dead", SEED_CATEGORY, "", "dead ends: dead"},
+		{DEAD_END_ALMANAC, SEED_CATEGORY, LOCATION_CATEGORY, "", "no route from seed to location"},
+	}
+
+	for _, test := range tests {
+		route, err := parseAlmanac(t, test.almanac).Route(test.from, test.to)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("route from %s to %s: %v", test.from, test.to, err)
+		case test.err == "" && route.String() != test.route:
+			t.Errorf("route from %s to %s: %s, expected %s", test.from, test.to, route, test.route)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("route from %s to %s: error %v, expected %q", test.from, test.to, err, test.err)
+		}
+	}
+
+	_, err := parseAlmanac(t, DEAD_END_ALMANAC).Route(SEED_CATEGORY, LOCATION_CATEGORY)
+	if !errors.Is(err, ErrNoRoute) {
+		t.Errorf("route through the dead ends: %v, expected ErrNoRoute", err)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	tests := []struct {
+		almanac string
+		cycles  [][]string
+	}{
+		{BRANCHING_ALMANAC, nil},
+		{DEAD_END_ALMANAC, [][]string{{"soil", "water", "soil"}}},
+	}
+
+	for i, test := range tests {
+		if cycles := parseAlmanac(t, test.almanac).Cycles(); !reflect.DeepEqual(cycles, test.cycles) {
+			t.Errorf("almanac %d: cycles %v, expected %v", i, cycles, test.cycles)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	almanac := sampleAlmanac(t)
+	tests := []struct{ seed, location int }{
+		{79, 82}, {14, 43}, {55, 86}, {13, 35},
+	}
+
+	for _, test := range tests {
+		location, err := almanac.Convert(SEED_CATEGORY, LOCATION_CATEGORY, test.seed)
+		if err != nil || location != test.location {
+			t.Errorf("seed %d: location %d (%v), expected %d", test.seed, location, err, test.location)
+		}
+	}
+}
+
+func TestCategoryMapInverse(t *testing.T) {
+	// 10..14 are moved to 20..24, the rest stays the same
+	categoryMap := &CategoryMap{"a", "b", []Mapping{{Diff: 10, SourceRange: Range{Start: 10, Length: 5}}}}
+
+	intTests := []struct {
+		dest    int
+		sources []int
+	}{
+		{5, []int{5}},
+		{12, nil},
+		{22, []int{12, 22}},
+	}
+	for _, test := range intTests {
+		if sources := categoryMap.InverseInt(test.dest); !reflect.DeepEqual(sources, test.sources) {
+			t.Errorf("inverse of %d: %v, expected %v", test.dest, sources, test.sources)
+		}
+	}
+
+	rangeTests := []struct {
+		dest    Range
+		sources []Range
+	}{
+		{Range{0, 5}, []Range{{0, 5}}},
+		{Range{10, 5}, []Range{}},
+		{Range{20, 10}, []Range{{10, 5}, {20, 10}}},
+		{Range{8, 4}, []Range{{8, 2}}},
+	}
+	for _, test := range rangeTests {
+		if sources := categoryMap.InverseRange(test.dest); !reflect.DeepEqual(sources, test.sources) {
+			t.Errorf("inverse of %v: %v, expected %v", test.dest, sources, test.sources)
+		}
+	}
+}
+
+func TestRouteInverse(t *testing.T) {
+	route, err := sampleAlmanac(t).Route(SEED_CATEGORY, LOCATION_CATEGORY)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		location int
+		seed     int
+	}{
+		{82, 79}, {43, 14}, {86, 55}, {35, 13},
+	}
+	for _, test := range tests {
+		if seeds := route.InverseInt(test.location); !slices.Contains(seeds, test.seed) {
+			t.Errorf("seeds of location %d: %v, expected %d among them", test.location, seeds, test.seed)
+		}
+
+		seedRanges := route.InverseRanges([]Range{{test.location, 1}})
+		if !rangesContain(seedRanges, test.seed) {
+			t.Errorf("seed ranges of location %d: %v, expected %d within them", test.location, seedRanges, test.seed)
+		}
+	}
+
+	for _, ranges := range [][]Range{{{0, 100}}, {{46, 1}}, {{10, 5}, {60, 20}}} {
+		if err := route.CheckInverse(ranges); err != nil {
+			t.Errorf("check inverse of %v: %v", ranges, err)
+		}
+	}
+}
+
+func TestRanges(t *testing.T) {
+	normalized := Ranges{{5, 3}, {1, 2}, {3, 1}, {20, 0}, {10, 5}, {12, 1}}.Normalize()
+	if expected := (Ranges{{1, 3}, {5, 3}, {10, 5}}); !reflect.DeepEqual(normalized, expected) {
+		t.Errorf("normalized %v, expected %v", normalized, expected)
+	}
+
+	tests := []struct {
+		ranges Ranges
+		cut    Range
+		result Ranges
+	}{
+		{Ranges{{0, 10}, {20, 5}}, Range{5, 17}, Ranges{{0, 5}, {22, 3}}},
+		{Ranges{{0, 10}}, Range{2, 3}, Ranges{{0, 2}, {5, 5}}},
+		{Ranges{{0, 10}}, Range{0, 10}, Ranges{}},
+		{Ranges{{0, 10}}, Range{10, 5}, Ranges{{0, 10}}},
+	}
+	for _, test := range tests {
+		if result := test.ranges.Without(test.cut); !reflect.DeepEqual(result, test.result) {
+			t.Errorf("%v without %v: %v, expected %v", test.ranges, test.cut, result, test.result)
+		}
+	}
+}
+
+func rangesContain(ranges []Range, number int) bool {
+	for _, range_ := range ranges {
+		if number >= range_.Start && number < range_.Start+range_.Length {
+			return true
+		}
+	}
+	return false
+}
@@ -1,9 +1,12 @@
 package day06
 
 import (
+	"aoc2023/input"
 	"bufio"
+	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -14,28 +17,52 @@ type Race struct {
 	Distance int
 }
 
+const TITLE = "Wait For It"
 const INPUT_FILE_PATH = "day06/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 func Run() {
-	races, err := readInputPart1(readLines(INPUT_FILE_PATH))
+	inputLines := readLines(INPUT_FILE_PATH)
+
+	product, err := Part1(inputLines)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Product of better times [PART 1]: ", product)
+
+	betterTimes, err := Part2(inputLines)
 	if err != nil {
 		panic(err)
 	}
 
+	fmt.Println("Better times for race [PART 2]: ", betterTimes)
+}
+
+func Part1(inputLines []string) (int, error) {
+	races, err := readInputPart1(inputLines)
+	if err != nil {
+		return 0, err
+	}
+
 	product := 1
 	for _, race := range races {
 		numBetterTimes := CountBetterTimes(race)
 		product *= numBetterTimes
 	}
 
-	fmt.Println("Product of better times [PART 1]: ", product)
+	return product, nil
+}
 
-	race, err := readInputPart2(readLines(INPUT_FILE_PATH))
+func Part2(inputLines []string) (int, error) {
+	race, err := readInputPart2(inputLines)
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
 
-	fmt.Println("Better times for race [PART 2]: ", CountBetterTimes(*race))
+	return CountBetterTimes(*race), nil
 }
 
 func CountBetterTimes(race Race) int {
@@ -65,11 +92,9 @@ func readInputPart1(lines []string) ([]Race, error) {
 
 	timesString, _ := strings.CutPrefix(lines[0], "Time:")
 	timeStrings := strings.Fields(timesString)
-	fmt.Println(timeStrings)
 
 	distancesString, _ := strings.CutPrefix(lines[1], "Distance:")
 	distanceStrings := strings.Fields(distancesString)
-	fmt.Println(distanceStrings)
 
 	if len(timeStrings) != len(distanceStrings) {
 		return nil, errors.New("Invalid input: unequal number of times and distances")
@@ -101,11 +126,9 @@ func readInputPart2(lines []string) (*Race, error) {
 
 	timesString, _ := strings.CutPrefix(lines[0], "Time:")
 	timeStrings := strings.Fields(timesString)
-	fmt.Println(timeStrings)
 
 	distancesString, _ := strings.CutPrefix(lines[1], "Distance:")
 	distanceStrings := strings.Fields(distancesString)
-	fmt.Println(distanceStrings)
 
 	if len(timeStrings) != len(distanceStrings) {
 		return nil, errors.New("Invalid input: unequal number of times and distances")
@@ -127,6 +150,33 @@ func readInputPart2(lines []string) (*Race, error) {
 	return &Race{Time: time, Distance: distance}, nil
 }
 
+func Parse(reader io.Reader) ([]Race, error) {
+	inputLines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return readInputPart1(inputLines)
+}
+
+// Format writes the times and distances of the races in right aligned columns
+func Format(writer io.Writer, races []Race) error {
+	buffered := bufio.NewWriter(writer)
+	timesString := fmt.Sprintf("%-9s", "Time:")
+	distancesString := "Distance:"
+
+	for _, race := range races {
+		timeString := strconv.Itoa(race.Time)
+		distanceString := strconv.Itoa(race.Distance)
+		width := max(len(timeString), len(distanceString)) + 2
+
+		timesString += fmt.Sprintf("%*s", width, timeString)
+		distancesString += fmt.Sprintf("%*s", width, distanceString)
+	}
+
+	fmt.Fprintf(buffered, "%s\n%s\n", timesString, distancesString)
+	return buffered.Flush()
+}
+
 func readLines(path string) []string {
 	// Read input file (line by line) from input.txt
 	fd, err := os.Open(path)
@@ -0,0 +1,10 @@
+package day06
+
+import (
+	"aoc2023/sampletest"
+	"testing"
+)
+
+func TestParseFormatRoundTrip(t *testing.T) {
+	sampletest.RoundTrip(t, Samples, Parse, Format, Part1, Part2)
+}
@@ -1,16 +1,26 @@
 package day07
 
 import (
+	"aoc2023/input"
+	"aoc2023/trace"
 	"bufio"
+	"bytes"
+	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
 )
 
+const TITLE = "Camel Cards"
 const INPUT_FILE_PATH = "day07/input.txt"
+
+//go:embed samples
+var Samples embed.FS
+
 const CARDS_ORDER_PART_1 = "23456789TJQKA"
 const CARDS_ORDER_PART_2 = "J23456789TQKA"
 
@@ -22,6 +32,16 @@ const TWO_PAIRS = 3
 const ONE_PAIR = 2
 const HIGH_CARD = 1
 
+var HAND_TYPE_NAMES = map[int]string{
+	FIVE_OF_A_KIND:  "five of a kind",
+	FOUR_OF_A_KIND:  "four of a kind",
+	FULL_HOUSE:      "full house",
+	THREE_OF_A_KIND: "three of a kind",
+	TWO_PAIRS:       "two pairs",
+	ONE_PAIR:        "one pair",
+	HIGH_CARD:       "high card",
+}
+
 type Hand struct {
 	Cards []byte
 	Bid   int
@@ -121,6 +141,12 @@ func (hand Hand) ValuePart1() int {
 }
 
 func (hand Hand) ValuePart2() int {
+	return hand.TracedValuePart2(nil)
+}
+
+// TracedValuePart2 values the hand like ValuePart2 and traces the rule deciding
+// what the jokers turn the hand into
+func (hand Hand) TracedValuePart2(tracer *trace.Tracer) int {
 	cardMap := make(map[byte]int)
 	for _, card := range hand.Cards {
 		cardMap[card]++
@@ -136,62 +162,77 @@ func (hand Hand) ValuePart2() int {
 		multiples[value]++
 	}
 
-	if cardMap[byte('J')] == 5 {
-		return FIVE_OF_A_KIND
+	jokers := cardMap[byte('J')]
+	value, rule := valuePart2(jokers, multiples)
+
+	// the hands are valued while sorting, the fields are only built for a trace
+	if tracer.Enabled() {
+		tracer.Emit("count", trace.Fields{
+			"hand": string(hand.Cards), "jokers": jokers,
+			"fives": multiples[5], "fours": multiples[4], "threes": multiples[3], "pairs": multiples[2],
+		})
+		tracer.Emit("type", trace.Fields{"part": 2, "type": HAND_TYPE_NAMES[value], "rule": rule})
 	}
-	if cardMap[byte('J')] == 4 {
-		return FIVE_OF_A_KIND
+	return value
+}
+
+func valuePart2(jokers int, multiples map[int]int) (int, string) {
+	if jokers == 5 {
+		return FIVE_OF_A_KIND, "five jokers"
 	}
-	if cardMap[byte('J')] == 3 && multiples[2] == 1 {
-		return FIVE_OF_A_KIND
+	if jokers == 4 {
+		return FIVE_OF_A_KIND, "four jokers join the last card"
 	}
-	if cardMap[byte('J')] == 3 {
-		return FOUR_OF_A_KIND
+	if jokers == 3 && multiples[2] == 1 {
+		return FIVE_OF_A_KIND, "three jokers join the pair"
 	}
-	if cardMap[byte('J')] == 2 && multiples[3] == 1 {
-		return FIVE_OF_A_KIND
+	if jokers == 3 {
+		return FOUR_OF_A_KIND, "three jokers join one of the single cards"
 	}
-	if cardMap[byte('J')] == 2 && multiples[2] == 1 {
-		return FOUR_OF_A_KIND
+	if jokers == 2 && multiples[3] == 1 {
+		return FIVE_OF_A_KIND, "two jokers join the three of a kind"
 	}
-	if cardMap[byte('J')] == 2 {
-		return THREE_OF_A_KIND
+	if jokers == 2 && multiples[2] == 1 {
+		return FOUR_OF_A_KIND, "two jokers join the pair"
 	}
-	if cardMap[byte('J')] == 1 && multiples[4] == 1 {
-		return FIVE_OF_A_KIND
+	if jokers == 2 {
+		return THREE_OF_A_KIND, "two jokers join one of the single cards"
 	}
-	if cardMap[byte('J')] == 1 && multiples[3] == 1 {
-		return FOUR_OF_A_KIND
+	if jokers == 1 && multiples[4] == 1 {
+		return FIVE_OF_A_KIND, "the joker joins the four of a kind"
 	}
-	if cardMap[byte('J')] == 1 && multiples[2] == 2 {
-		return FULL_HOUSE
+	if jokers == 1 && multiples[3] == 1 {
+		return FOUR_OF_A_KIND, "the joker joins the three of a kind"
 	}
-	if cardMap[byte('J')] == 1 && multiples[2] == 1 {
-		return THREE_OF_A_KIND
+	if jokers == 1 && multiples[2] == 2 {
+		return FULL_HOUSE, "the joker joins one of the two pairs"
 	}
-	if cardMap[byte('J')] == 1 {
-		return ONE_PAIR
+	if jokers == 1 && multiples[2] == 1 {
+		return THREE_OF_A_KIND, "the joker joins the pair"
+	}
+	if jokers == 1 {
+		return ONE_PAIR, "the joker joins one of the single cards"
 	}
 
 	if multiples[5] > 0 {
-		return FIVE_OF_A_KIND
+		return FIVE_OF_A_KIND, "no jokers, five of the same card"
 	}
 	if multiples[4] > 0 {
-		return FOUR_OF_A_KIND
+		return FOUR_OF_A_KIND, "no jokers, four of the same card"
 	}
 	if multiples[3] == 1 && multiples[2] == 1 {
-		return FULL_HOUSE
+		return FULL_HOUSE, "no jokers, three of a kind and a pair"
 	}
 	if multiples[3] == 1 {
-		return THREE_OF_A_KIND
+		return THREE_OF_A_KIND, "no jokers, three of the same card"
 	}
 	if multiples[2] == 2 {
-		return TWO_PAIRS
+		return TWO_PAIRS, "no jokers, two pairs"
 	}
 	if multiples[2] == 1 {
-		return ONE_PAIR
+		return ONE_PAIR, "no jokers, a single pair"
 	}
-	return HIGH_CARD
+	return HIGH_CARD, "no jokers, all cards differ"
 }
 
 func CardValuePart1(card byte) int {
@@ -214,33 +255,91 @@ func CardValuePart2(card byte) int {
 
 func Run() {
 	lines := readLines(INPUT_FILE_PATH)
-	hands, err := parseInput(lines)
+
+	totalWinningsPart1, err := Part1(lines)
 	if err != nil {
 		panic(err)
 	}
+	fmt.Println("Total winnings [PART 1]: ", totalWinningsPart1)
 
-	var handsPart1 HandsPart1 = make(HandsPart1, len(hands))
-	var handsPart2 HandsPart2 = make(HandsPart2, len(hands))
+	totalWinningsPart2, err := Part2(lines)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Total winnings [PART 2]: ", totalWinningsPart2)
+}
 
-	copy(handsPart1, hands)
-	copy(handsPart2, hands)
+func Part1(lines []string) (int, error) {
+	hands, err := parseInput(lines)
+	if err != nil {
+		return 0, err
+	}
 
+	var handsPart1 HandsPart1 = make(HandsPart1, len(hands))
+	copy(handsPart1, hands)
 	sort.Sort(&handsPart1)
-	sort.Sort(&handsPart2)
 
-	totalWinningsPart1 := 0
+	totalWinnings := 0
 	for rank, hand := range handsPart1 {
 		winnings := hand.Bid * (rank + 1)
-		totalWinningsPart1 += winnings
+		totalWinnings += winnings
+	}
+	return totalWinnings, nil
+}
+
+func Part2(lines []string) (int, error) {
+	hands, err := parseInput(lines)
+	if err != nil {
+		return 0, err
 	}
-	fmt.Println("Total winnings [PART 1]: ", totalWinningsPart1)
 
-	totalWinningsPart2 := 0
+	var handsPart2 HandsPart2 = make(HandsPart2, len(hands))
+	copy(handsPart2, hands)
+	sort.Sort(&handsPart2)
+
+	totalWinnings := 0
 	for rank, hand := range handsPart2 {
 		winnings := hand.Bid * (rank + 1)
-		totalWinningsPart2 += winnings
+		totalWinnings += winnings
 	}
-	fmt.Println("Total winnings [PART 2]: ", totalWinningsPart2)
+	return totalWinnings, nil
+}
+
+// Explain traces the type and the rank of the hand on the given line (from 1) in both parts
+func Explain(lines []string, line int, tracer *trace.Tracer) error {
+	hands, err := parseInput(lines)
+	if err != nil {
+		return err
+	}
+	if line < 1 || line > len(hands) {
+		return fmt.Errorf("no hand on line %d, there are %d hands", line, len(hands))
+	}
+
+	hand := hands[line-1]
+	tracer.Emit("hand", trace.Fields{"cards": string(hand.Cards), "bid": hand.Bid})
+	tracer.Emit("type", trace.Fields{"part": 1, "type": HAND_TYPE_NAMES[hand.ValuePart1()]})
+	hand.TracedValuePart2(tracer)
+
+	var handsPart1 HandsPart1 = make(HandsPart1, len(hands))
+	copy(handsPart1, hands)
+	sort.Sort(&handsPart1)
+
+	var handsPart2 HandsPart2 = make(HandsPart2, len(hands))
+	copy(handsPart2, hands)
+	sort.Sort(&handsPart2)
+
+	for part, sortedHands := range [][]Hand{handsPart1, handsPart2} {
+		for rank, sortedHand := range sortedHands {
+			if bytes.Equal(sortedHand.Cards, hand.Cards) && sortedHand.Bid == hand.Bid {
+				tracer.Emit("rank", trace.Fields{
+					"part": part + 1, "rank": rank + 1, "of": len(hands), "winnings": hand.Bid * (rank + 1),
+				})
+				break
+			}
+		}
+	}
+
+	return nil
 }
 
 func parseInput(lines []string) ([]Hand, error) {
@@ -280,6 +379,22 @@ func parseHand(line string) (*Hand, error) {
 	return &hand, nil
 }
 
+func Parse(reader io.Reader) ([]Hand, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(lines)
+}
+
+func Format(writer io.Writer, hands []Hand) error {
+	buffered := bufio.NewWriter(writer)
+	for _, hand := range hands {
+		fmt.Fprintf(buffered, "%s %d\n", hand.Cards, hand.Bid)
+	}
+	return buffered.Flush()
+}
+
 func readLines(path string) []string {
 	// Read input file (line by line) from input.txt
 	fd, err := os.Open(path)
@@ -1,14 +1,23 @@
 package day08
 
 import (
+	"aoc2023/input"
 	"bufio"
+	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+	"sort"
 )
 
+const TITLE = "Haunted Wasteland"
 const INPUT_FILE_PATH = "day08/input.txt"
+
+//go:embed samples
+var Samples embed.FS
+
 const NETWORK_REGEX = `^(\w+) = \((\w+), (\w+)\)$`
 
 type Navigation struct {
@@ -23,18 +32,36 @@ type Node struct {
 
 func Run() {
 	inputLines := readLines(INPUT_FILE_PATH)
-	navigation, err := parseInput(inputLines)
+
+	stepsPart1, err := Part1(inputLines)
 	if err != nil {
 		panic(err)
 	}
-
-	stepsPart1 := findWayOutPart1(navigation)
 	fmt.Println("Steps to exit [PART 1]: ", stepsPart1)
 
-	stepsPart2 := findWayOutPart2(navigation)
+	stepsPart2, err := Part2(inputLines)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("Steps to exit [PART 2]: ", stepsPart2)
 }
 
+func Part1(inputLines []string) (int, error) {
+	navigation, err := parseInput(inputLines)
+	if err != nil {
+		return 0, err
+	}
+	return findWayOutPart1(navigation), nil
+}
+
+func Part2(inputLines []string) (int, error) {
+	navigation, err := parseInput(inputLines)
+	if err != nil {
+		return 0, err
+	}
+	return findWayOutPart2(navigation), nil
+}
+
 func findWayOutPart2(navigation *Navigation) int {
 	locations := make(map[string]int)
 
@@ -96,6 +123,10 @@ func findWayOut(navigation *Navigation, start string, ended func(string) bool) i
 func parseInput(lines []string) (*Navigation, error) {
 	var navigation Navigation
 	navigation.Network = make(map[string]Node)
+
+	if len(lines) == 0 {
+		return nil, errors.New("invalid input")
+	}
 	navigation.LeftRights = lines[0]
 
 	for _, line := range lines[1:] {
@@ -116,6 +147,33 @@ func parseInput(lines []string) (*Navigation, error) {
 	return &navigation, nil
 }
 
+func Parse(reader io.Reader) (*Navigation, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(lines)
+}
+
+// Format writes the instructions and the network, the nodes sorted by name
+func Format(writer io.Writer, navigation *Navigation) error {
+	buffered := bufio.NewWriter(writer)
+	fmt.Fprintf(buffered, "%s\n\n", navigation.LeftRights)
+
+	names := make([]string, 0, len(navigation.Network))
+	for name := range navigation.Network {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		node := navigation.Network[name]
+		fmt.Fprintf(buffered, "%s = (%s, %s)\n", name, node.Left, node.Right)
+	}
+
+	return buffered.Flush()
+}
+
 func readLines(path string) []string {
 	// Read input file (line by line) from input.txt
 	fd, err := os.Open(path)
@@ -1,30 +1,65 @@
 package day09
 
 import (
+	"aoc2023/input"
 	"bufio"
+	"embed"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 )
 
+const TITLE = "Mirage Maintenance"
 const INPUT_FILE_PATH = "day09/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 func Run() {
 	lines := readLines(INPUT_FILE_PATH)
-	sequences := parseInput(lines)
 
-	sumOfExtrapolatedStarts := 0
-	sumOfExtrapolatedEnds := 0
+	sumOfExtrapolatedEnds, err := Part1(lines)
+	if err != nil {
+		panic(err)
+	}
 
+	sumOfExtrapolatedStarts, err := Part2(lines)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Sum of extrapolated values [PART 1]: ", sumOfExtrapolatedEnds)
+	fmt.Println("Sum of extrapolated values [PART 2]: ", sumOfExtrapolatedStarts)
+}
+
+func Part1(lines []string) (int, error) {
+	sequences, err := parseInput(lines)
+	if err != nil {
+		return 0, err
+	}
+
+	sumOfExtrapolatedEnds := 0
 	for _, sequence := range sequences {
 		extrapolatedSequence := extrapolateSequence(sequence)
-		sumOfExtrapolatedStarts += extrapolatedSequence[0]
 		sumOfExtrapolatedEnds += extrapolatedSequence[len(extrapolatedSequence)-1]
 	}
+	return sumOfExtrapolatedEnds, nil
+}
 
-	fmt.Println("Sum of extrapolated values [PART 1]: ", sumOfExtrapolatedEnds)
-	fmt.Println("Sum of extrapolated values [PART 2]: ", sumOfExtrapolatedStarts)
+func Part2(lines []string) (int, error) {
+	sequences, err := parseInput(lines)
+	if err != nil {
+		return 0, err
+	}
+
+	sumOfExtrapolatedStarts := 0
+	for _, sequence := range sequences {
+		extrapolatedSequence := extrapolateSequence(sequence)
+		sumOfExtrapolatedStarts += extrapolatedSequence[0]
+	}
+	return sumOfExtrapolatedStarts, nil
 }
 
 func extrapolateSequence(sequence []int) []int {
@@ -65,7 +100,7 @@ func extrapolateSequence(sequence []int) []int {
 	return sequences[0]
 }
 
-func parseInput(lines []string) [][]int {
+func parseInput(lines []string) ([][]int, error) {
 	sequences := [][]int{}
 
 	for _, line := range lines {
@@ -75,7 +110,7 @@ func parseInput(lines []string) [][]int {
 		for j, token := range tokens {
 			number, err := strconv.Atoi(token)
 			if err != nil {
-				panic(err)
+				return nil, fmt.Errorf("invalid number in sequence: %s", token)
 			}
 			sequence[j] = number
 		}
@@ -83,7 +118,29 @@ func parseInput(lines []string) [][]int {
 		sequences = append(sequences, sequence)
 	}
 
-	return sequences
+	return sequences, nil
+}
+
+func Parse(reader io.Reader) ([][]int, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(lines)
+}
+
+func Format(writer io.Writer, sequences [][]int) error {
+	buffered := bufio.NewWriter(writer)
+
+	for _, sequence := range sequences {
+		tokens := make([]string, len(sequence))
+		for i, number := range sequence {
+			tokens[i] = strconv.Itoa(number)
+		}
+		fmt.Fprintln(buffered, strings.Join(tokens, " "))
+	}
+
+	return buffered.Flush()
 }
 
 func readLines(path string) []string {
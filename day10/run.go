@@ -1,17 +1,25 @@
 package day10
 
 import (
+	"aoc2023/export"
+	"aoc2023/input"
+	"aoc2023/visual"
 	"bufio"
+	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
-
-	"github.com/fatih/color"
+	"strings"
 )
 
+const TITLE = "Pipe Maze"
 const INPUT_FILE_PATH = "day10/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 const INVALID = 0
 const UP = 1
 const DOWN = -1
@@ -41,21 +49,7 @@ func (byteMap *ByteMap) Print() {
 }
 
 func (byteMap *ByteMap) PrintLoopAndInsidePoints(loop *Loop, sweepedPoints []Point) {
-	red := color.New(color.FgRed)
-	blue := color.New(color.FgBlue)
-
-	for i := 0; i < byteMap.DimY; i++ {
-		for j := 0; j < byteMap.DimX; j++ {
-			if slices.Contains(*loop, Point{j, i}) {
-				red.Print(string(byteMap.Bytes[i][j]))
-			} else if slices.Contains(sweepedPoints, Point{j, i}) {
-				blue.Print(string(byteMap.Bytes[i][j]))
-			} else {
-				fmt.Print(string(byteMap.Bytes[i][j]))
-			}
-		}
-		fmt.Println()
-	}
+	visual.RenderFrame(os.Stdout, byteMap.Frame(loop, sweepedPoints))
 }
 
 func (byteMap *ByteMap) Get(X int, Y int) Tile {
@@ -82,6 +76,40 @@ func Run() {
 	fmt.Println("Sweeped points [PART 2]: ", len(sweepedPoints))
 
 	byteMap.PrintLoopAndInsidePoints(loop, sweepedPoints)
+
+	if export.Enabled() {
+		export.WriteGrid("day10", byteMap.Frame(loop, sweepedPoints))
+	}
+}
+
+func Part1(lines []string) (int, error) {
+	loop, err := DiscoverLoop(parseInput(lines))
+	if err != nil {
+		return 0, err
+	}
+	return (len(*loop) + 1) / 2, nil
+}
+
+func Part2(lines []string) (int, error) {
+	byteMap := parseInput(lines)
+	loop, err := DiscoverLoop(byteMap)
+	if err != nil {
+		return 0, err
+	}
+	return len(byteMap.SweepAndFindInnerPoints(loop)), nil
+}
+
+func (byteMap *ByteMap) Frame(loop *Loop, sweepedPoints []Point) visual.Frame {
+	frame := visual.NewFrame(byteMap.Bytes, "Loop and inside points")
+
+	for _, point := range *loop {
+		frame.SetStyle(point.X, point.Y, visual.LOOP)
+	}
+	for _, point := range sweepedPoints {
+		frame.SetStyle(point.X, point.Y, visual.INSIDE)
+	}
+
+	return frame
 }
 
 func DiscoverLoop(byteMap *ByteMap) (*Loop, error) {
@@ -281,6 +309,41 @@ func parseInput(lines []string) *ByteMap {
 	return &ByteMap{bytes, dimX, dimY}
 }
 
+func Parse(reader io.Reader) (*ByteMap, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("empty map")
+	}
+	return parseInput(lines), nil
+}
+
+func Format(writer io.Writer, byteMap *ByteMap) error {
+	buffered := bufio.NewWriter(writer)
+	for _, row := range byteMap.Bytes {
+		buffered.Write(row)
+		buffered.WriteByte('\n')
+	}
+	return buffered.Flush()
+}
+
+// Validate checks that the map is a rectangle of pipes with a single start
+func Validate(lines []string) []error {
+	problems := input.CheckGrid(lines, "|-LJ7F.S", 1)
+
+	starts := 0
+	for _, line := range lines {
+		starts += strings.Count(line, "S")
+	}
+	if starts != 1 {
+		problems = append(problems, fmt.Errorf("expected a single start S, found %d", starts))
+	}
+
+	return problems
+}
+
 func readLines(path string) []string {
 	// Read input file (line by line) from input.txt
 	fd, err := os.Open(path)
@@ -1,14 +1,24 @@
 package day11
 
 import (
+	"aoc2023/input"
+	"aoc2023/parallel"
 	"bufio"
+	"embed"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
+	"time"
 )
 
+const TITLE = "Cosmic Expansion"
 const INPUT_FILE_PATH = "day11/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 type Point struct {
 	X int
 	Y int
@@ -117,21 +127,49 @@ func Run() {
 	expandedImage := image.Expand(image)
 	galaxies := expandedImage.FindGalaxies()
 
-	sumDistancesPart1 := 0
-	for i := 0; i < len(galaxies); i++ {
-		for j := i + 1; j < len(galaxies); j++ {
-			sumDistancesPart1 += image.Distance(galaxies[i], galaxies[j], 2)
-		}
+	var startTime time.Time
+	var elapsedTime time.Duration
+
+	startTime = time.Now()
+	sumDistancesPart1 := image.SumOfDistances(galaxies, 2)
+	elapsedTime = time.Since(startTime)
+
+	fmt.Println("Sum of distances [PART 1]: ", sumDistancesPart1,
+		";\tWorkers: ", parallel.Workers, ";\tElapsed time: ", elapsedTime)
+
+	startTime = time.Now()
+	sumDistancesPart2 := image.SumOfDistances(galaxies, 1000000)
+	elapsedTime = time.Since(startTime)
+
+	fmt.Println("Sum of distances [PART 2]: ", sumDistancesPart2,
+		";\tWorkers: ", parallel.Workers, ";\tElapsed time: ", elapsedTime)
+}
+
+func Part1(lines []string) (int, error) {
+	image := parseInput(lines).Expand(nil)
+	return image.SumOfDistances(image.FindGalaxies(), 2), nil
+}
+
+func Part2(lines []string) (int, error) {
+	image := parseInput(lines).Expand(nil)
+	return image.SumOfDistances(image.FindGalaxies(), 1000000), nil
+}
+
+// SumOfDistances sums the distances of all galaxy pairs, the pairs of every
+// galaxy with the galaxies after it are summed independently on the worker pool
+func (image *Image) SumOfDistances(galaxies []Point, emptyCoef int) int {
+	indices := make([]int, len(galaxies))
+	for i := range indices {
+		indices[i] = i
 	}
-	fmt.Println("Sum of distances [PART 1]: ", sumDistancesPart1)
 
-	sumDistancesPart2 := 0
-	for i := 0; i < len(galaxies); i++ {
+	return parallel.Sum(indices, func(i int) int {
+		sumDistances := 0
 		for j := i + 1; j < len(galaxies); j++ {
-			sumDistancesPart2 += image.Distance(galaxies[i], galaxies[j], 1000000)
+			sumDistances += image.Distance(galaxies[i], galaxies[j], emptyCoef)
 		}
-	}
-	fmt.Println("Sum of distances [PART 2]: ", sumDistancesPart2)
+		return sumDistances
+	})
 }
 
 func parseInput(lines []string) *Image {
@@ -147,6 +185,30 @@ func parseInput(lines []string) *Image {
 	return &image
 }
 
+func Parse(reader io.Reader) (*Image, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("empty image")
+	}
+	return parseInput(lines), nil
+}
+
+func Format(writer io.Writer, image *Image) error {
+	buffered := bufio.NewWriter(writer)
+	for _, row := range image.Bytes {
+		buffered.Write(row)
+		buffered.WriteByte('\n')
+	}
+	return buffered.Flush()
+}
+
+func Validate(lines []string) []error {
+	return input.CheckGrid(lines, ".#", 1)
+}
+
 func readLines(path string) []string {
 	// Read input file (line by line) from input.txt
 	fd, err := os.Open(path)
@@ -1,42 +1,78 @@
 package day12
 
 import (
+	"aoc2023/input"
+	"aoc2023/parallel"
 	"bufio"
+	"embed"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
+const TITLE = "Hot Springs"
 const INPUT_FILE_PATH = "day12/input.txt"
+
+//go:embed samples
+var Samples embed.FS
+
 const UNFOLD_MULTIPLIER = 5
 
 func Run() {
 	lines := readLines(INPUT_FILE_PATH)
-	springRows := parseInput(lines)
-
-	sumOfCountOfValidVariants := 0
-	for _, springRow := range springRows {
-		sumOfCountOfValidVariants += SolveBruteForcefullyWithHeuristics(springRow)
+	springRows, err := parseInput(lines)
+	if err != nil {
+		panic(err)
 	}
 
-	fmt.Println("Sum of count of valid variants [PART 1]: ", sumOfCountOfValidVariants)
+	var startTime time.Time
+	var elapsedTime time.Duration
 
-	sumOfCountOfUnfoldedValidVariants := 0
-	for _, springRow := range springRows {
-		dynamicProgramming := DynamicProgramming{
-			Pattern: unfoldPattern(springRow.Pattern, UNFOLD_MULTIPLIER),
-			Groups:  unfoldGroups(springRow.Groups, UNFOLD_MULTIPLIER),
-		}
-		sumOfCountOfUnfoldedValidVariants += dynamicProgramming.Solve()
-	}
+	// every spring row is solved independently on the worker pool
+	startTime = time.Now()
+	sumOfCountOfValidVariants := parallel.Sum(springRows, SolveBruteForcefullyWithHeuristics)
+	elapsedTime = time.Since(startTime)
 
-	fmt.Println("Sum of count of valid variants [PART 2]: ", sumOfCountOfUnfoldedValidVariants)
+	fmt.Println("Sum of count of valid variants [PART 1]: ", sumOfCountOfValidVariants,
+		";\tWorkers: ", parallel.Workers, ";\tElapsed time: ", elapsedTime)
 
+	startTime = time.Now()
+	sumOfCountOfUnfoldedValidVariants := parallel.Sum(springRows, SolveUnfolded)
+	elapsedTime = time.Since(startTime)
+
+	fmt.Println("Sum of count of valid variants [PART 2]: ", sumOfCountOfUnfoldedValidVariants,
+		";\tWorkers: ", parallel.Workers, ";\tElapsed time: ", elapsedTime)
+}
+
+func Part1(lines []string) (int, error) {
+	springRows, err := parseInput(lines)
+	if err != nil {
+		return 0, err
+	}
+	return parallel.Sum(springRows, SolveBruteForcefullyWithHeuristics), nil
+}
+
+func Part2(lines []string) (int, error) {
+	springRows, err := parseInput(lines)
+	if err != nil {
+		return 0, err
+	}
+	return parallel.Sum(springRows, SolveUnfolded), nil
 }
 
 // PART 2
 
+func SolveUnfolded(springs Springs) int {
+	dynamicProgramming := DynamicProgramming{
+		Pattern: unfoldPattern(springs.Pattern, UNFOLD_MULTIPLIER),
+		Groups:  unfoldGroups(springs.Groups, UNFOLD_MULTIPLIER),
+	}
+	return dynamicProgramming.Solve()
+}
+
 func unfoldPattern(pattern string, coef int) string {
 	patternDups := []string{}
 	for i := 0; i < coef; i++ {
@@ -246,16 +282,23 @@ func areSpringsValid(springs string, actualGroupSizes []int) (bool, bool) {
 	return true, true
 }
 
-func parseInput(lines []string) []Springs {
+func parseInput(lines []string) ([]Springs, error) {
 	springRows := []Springs{}
 
 	for _, line := range lines {
 		words := strings.Split(line, " ")
+		if len(words) != 2 {
+			return nil, fmt.Errorf("invalid spring row: %s", line)
+		}
+
 		groupSizesAsStrings := strings.Split(words[1], ",")
 		groupSizes := []int{}
 
 		for _, groupSizeAsString := range groupSizesAsStrings {
-			groupSize, _ := strconv.Atoi(groupSizeAsString)
+			groupSize, err := strconv.Atoi(groupSizeAsString)
+			if err != nil {
+				return nil, fmt.Errorf("invalid group size in spring row: %s", line)
+			}
 			groupSizes = append(groupSizes, groupSize)
 		}
 
@@ -267,7 +310,29 @@ func parseInput(lines []string) []Springs {
 		springRows = append(springRows, springRow)
 	}
 
-	return springRows
+	return springRows, nil
+}
+
+func Parse(reader io.Reader) ([]Springs, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(lines)
+}
+
+func Format(writer io.Writer, springRows []Springs) error {
+	buffered := bufio.NewWriter(writer)
+
+	for _, springRow := range springRows {
+		groupSizesAsStrings := make([]string, len(springRow.Groups))
+		for i, groupSize := range springRow.Groups {
+			groupSizesAsStrings[i] = strconv.Itoa(groupSize)
+		}
+		fmt.Fprintf(buffered, "%s %s\n", springRow.Pattern, strings.Join(groupSizesAsStrings, ","))
+	}
+
+	return buffered.Flush()
 }
 
 func readLines(path string) []string {
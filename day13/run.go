@@ -1,29 +1,55 @@
 package day13
 
 import (
+	"aoc2023/input"
 	"bufio"
+	"embed"
+	"fmt"
+	"io"
 	"os"
 )
 
+const TITLE = "Point of Incidence"
 const INPUT_FILE_PATH = "day13/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 func Run() {
-	patterns := parsePatterns(readLines(INPUT_FILE_PATH))
-	sumOfNotesPart1, sumOfNotesPart2 := 0, 0
+	lines := readLines(INPUT_FILE_PATH)
+
+	sumOfNotesPart1, err := Part1(lines)
+	if err != nil {
+		panic(err)
+	}
 
-	for _, pattern := range patterns {
-		abovePart1 := pattern.FindHorizontalSymmetry()
-		leftPart1 := pattern.FindVerticalSymmetry()
+	sumOfNotesPart2, err := Part2(lines)
+	if err != nil {
+		panic(err)
+	}
 
-		abovePart2 := pattern.FindHorizontalSymmetryWithSmudge()
-		leftPart2 := pattern.FindVerticalSymmetryWithSmudge()
+	fmt.Println("Sum of notes [PART 1]: ", sumOfNotesPart1)
+	fmt.Println("Sum of notes [PART 2]: ", sumOfNotesPart2)
+}
 
-		sumOfNotesPart1 += abovePart1*100 + leftPart1
-		sumOfNotesPart2 += abovePart2*100 + leftPart2
+func Part1(lines []string) (int, error) {
+	sumOfNotes := 0
+	for _, pattern := range parsePatterns(lines) {
+		above := pattern.FindHorizontalSymmetry()
+		left := pattern.FindVerticalSymmetry()
+		sumOfNotes += above*100 + left
 	}
+	return sumOfNotes, nil
+}
 
-	println("Sum of notes [PART 1]: ", sumOfNotesPart1)
-	println("Sum of notes [PART 2]: ", sumOfNotesPart2)
+func Part2(lines []string) (int, error) {
+	sumOfNotes := 0
+	for _, pattern := range parsePatterns(lines) {
+		above := pattern.FindHorizontalSymmetryWithSmudge()
+		left := pattern.FindVerticalSymmetryWithSmudge()
+		sumOfNotes += above*100 + left
+	}
+	return sumOfNotes, nil
 }
 
 type Pattern struct {
@@ -222,6 +248,52 @@ func parsePatterns(lines []string) []Pattern {
 	return patterns
 }
 
+func Parse(reader io.Reader) ([]Pattern, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parsePatterns(lines), nil
+}
+
+// Format writes the patterns separated by empty lines
+func Format(writer io.Writer, patterns []Pattern) error {
+	buffered := bufio.NewWriter(writer)
+
+	for i, pattern := range patterns {
+		if i > 0 {
+			buffered.WriteByte('\n')
+		}
+		for _, row := range pattern.Bytes {
+			buffered.Write(row)
+			buffered.WriteByte('\n')
+		}
+	}
+
+	return buffered.Flush()
+}
+
+// Validate checks every pattern on its own, the patterns may differ in size
+func Validate(lines []string) []error {
+	var problems []error
+	firstLine := 0
+
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && lines[i] != "" {
+			continue
+		}
+		if i > firstLine {
+			problems = append(problems, input.CheckGrid(lines[firstLine:i], "#.", firstLine+1)...)
+		}
+		firstLine = i + 1
+	}
+
+	if len(lines) == 0 {
+		problems = append(problems, fmt.Errorf("no patterns"))
+	}
+	return problems
+}
+
 func readLines(path string) []string {
 	// Read input file (line by line) from input.txt
 	fd, err := os.Open(path)
@@ -1,32 +1,52 @@
 package day14
 
 import (
+	"aoc2023/input"
+	"aoc2023/visual"
 	"bufio"
+	"embed"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 )
 
+const TITLE = "Parabolic Reflector Dish"
 const INPUT_FILE_PATH = "day14/input.txt"
+
+//go:embed samples
+var Samples embed.FS
+
 const NUM_OF_CYCLES = 1000000000
 
 func Run() {
 	lines := readLines(INPUT_FILE_PATH)
 	platform := parseInput(lines)
 
+	visual.Default.Record(platform.Frame("Initial platform"))
 	northPlatform := platform.Tilt(NORTH)
+	visual.Default.Record(northPlatform.Frame("Tilted north [PART 1]"))
 	fmt.Println("Load [PART 1]: ", northPlatform.LoadOnNorthBeams())
 
-	platformAfterCycles := RunNCycles(platform, NUM_OF_CYCLES)
-	println("Load [PART 2]: ", platformAfterCycles.LoadOnNorthBeams())
+	platformAfterCycles := RunNCycles(platform, NUM_OF_CYCLES, visual.Default)
+	fmt.Println("Load [PART 2]: ", platformAfterCycles.LoadOnNorthBeams())
 }
 
-func RunNCycles(platform *Platform, n int) *Platform {
+func Part1(lines []string) (int, error) {
+	return parseInput(lines).Tilt(NORTH).LoadOnNorthBeams(), nil
+}
+
+func Part2(lines []string) (int, error) {
+	return RunNCycles(parseInput(lines), NUM_OF_CYCLES, nil).LoadOnNorthBeams(), nil
+}
+
+func RunNCycles(platform *Platform, n int, recorder *visual.Recorder) *Platform {
 	platformCache := []*Platform{platform}
 	startPeriod, endPeriod := -1, -1
 	periodFound := false
 
 	for it := 0; it < n && !periodFound; it++ {
-		newPlatform := platformCache[it].RunCycle()
+		newPlatform := platformCache[it].RunCycle(recorder, it+1)
 		for i := 0; i < it+1 && !periodFound; i++ {
 			if newPlatform.Compare(platformCache[i]) {
 				startPeriod, endPeriod = i, it+1
@@ -87,10 +107,45 @@ const (
 	WEST
 )
 
-func (platform *Platform) RunCycle() *Platform {
+func (platform *Platform) Frame(caption string) visual.Frame {
+	frame := visual.NewFrame(platform.Bytes, caption)
+
+	for y := 0; y < platform.DimY; y++ {
+		for x := 0; x < platform.DimX; x++ {
+			switch platform.Bytes[y][x] {
+			case 'O':
+				frame.SetStyle(x, y, visual.ROCK)
+			case '#':
+				frame.SetStyle(x, y, visual.WALL)
+			}
+		}
+	}
+
+	return frame
+}
+
+func (direction Direction) String() string {
+	switch direction {
+	case NORTH:
+		return "north"
+	case EAST:
+		return "east"
+	case SOUTH:
+		return "south"
+	case WEST:
+		return "west"
+	}
+	return ""
+}
+
+func (platform *Platform) RunCycle(recorder *visual.Recorder, cycle int) *Platform {
 	directions := []Direction{NORTH, WEST, SOUTH, EAST}
 	for _, direction := range directions {
 		platform = platform.Tilt(direction)
+		if recorder.Enabled() {
+			caption := fmt.Sprintf("Cycle %d, tilted %s [PART 2]", cycle, direction)
+			recorder.Record(platform.Frame(caption))
+		}
 	}
 	return platform
 }
@@ -222,6 +277,30 @@ func parseInput(lines []string) *Platform {
 	return &Platform{bytes, dimX, dimY}
 }
 
+func Parse(reader io.Reader) (*Platform, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("empty platform")
+	}
+	return parseInput(lines), nil
+}
+
+func Format(writer io.Writer, platform *Platform) error {
+	buffered := bufio.NewWriter(writer)
+	for _, row := range platform.Bytes {
+		buffered.Write(row)
+		buffered.WriteByte('\n')
+	}
+	return buffered.Flush()
+}
+
+func Validate(lines []string) []error {
+	return input.CheckGrid(lines, "O#.", 1)
+}
+
 func readLines(path string) []string {
 	fd, err := os.Open(path)
 	if err != nil {
@@ -1,15 +1,22 @@
 package day15
 
 import (
+	"aoc2023/input"
 	"bufio"
+	"embed"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 )
 
+const TITLE = "Lens Library"
 const INPUT_FILE_PATH = "day15/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 type Lense struct {
 	Label       string
 	FocalLength int
@@ -30,6 +37,18 @@ func Run() {
 	fmt.Println("Focus power [PART 2]: ", hashMap.FocusPower())
 }
 
+func Part1(lines []string) (int, error) {
+	totalHash := 0
+	for _, instruction := range parseInput(lines) {
+		totalHash += Hash(instruction)
+	}
+	return totalHash, nil
+}
+
+func Part2(lines []string) (int, error) {
+	return InitializeHashMap(parseInput(lines)).FocusPower(), nil
+}
+
 func (hashMap HashMap) FocusPower() int {
 	focusPower := 0
 
@@ -149,6 +168,20 @@ func parseInput(lines []string) []string {
 	return result
 }
 
+func Parse(reader io.Reader) ([]string, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(lines), nil
+}
+
+// Format writes the initialization sequence on a single line
+func Format(writer io.Writer, instructions []string) error {
+	_, err := fmt.Fprintln(writer, strings.Join(instructions, ","))
+	return err
+}
+
 func readLines(path string) []string {
 	fd, err := os.Open(path)
 	if err != nil {
@@ -1,13 +1,25 @@
 package day16
 
 import (
+	"aoc2023/export"
+	"aoc2023/input"
+	"aoc2023/parallel"
+	"aoc2023/visual"
 	"bufio"
+	"bytes"
+	"embed"
 	"fmt"
+	"io"
 	"os"
+	"time"
 )
 
+const TITLE = "The Floor Will Be Lava"
 const INPUT_FILE_PATH = "day16/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 type Direction int
 
 const (
@@ -18,12 +30,15 @@ const (
 )
 
 type Contraption struct {
-	Grid  [][]byte
-	Beams [][]Direction
-	DimX  int
-	DimY  int
+	Grid [][]byte
+	DimX int
+	DimY int
 }
 
+// Beams holds the directions of the beams passing through every tile,
+// each simulation gets its own Beams so they can run concurrently
+type Beams [][]Direction
+
 type Position struct {
 	X        int
 	Y        int
@@ -34,58 +49,91 @@ func Run() {
 	lines := readLines(INPUT_FILE_PATH)
 	contraption := parseInput(lines)
 
-	energizedTilesFromTopLeft := contraption.EnergizedTiles(Position{0, 0, RIGHT})
-	fmt.Println("Number of energized tiles from top left [PART 1]: ", energizedTilesFromTopLeft)
+	beams := contraption.Energize(Position{0, 0, RIGHT}, visual.Default)
+	fmt.Println("Number of energized tiles from top left [PART 1]: ", beams.CountEnergized())
+
+	if export.Enabled() {
+		export.WriteGrid("day16", contraption.Heatmap(beams))
+	}
 
+	startTime := time.Now()
 	maxEnergizedTiles := contraption.FindMaxEnergizedTiles()
-	fmt.Println("Max number of energized tiles [PART 2]: ", maxEnergizedTiles)
+	elapsedTime := time.Since(startTime)
+
+	fmt.Println("Max number of energized tiles [PART 2]: ", maxEnergizedTiles,
+		";\tWorkers: ", parallel.Workers, ";\tElapsed time: ", elapsedTime)
+}
+
+func Part1(lines []string) (int, error) {
+	return parseInput(lines).EnergizedTiles(Position{0, 0, RIGHT}), nil
+}
 
+func Part2(lines []string) (int, error) {
+	return parseInput(lines).FindMaxEnergizedTiles(), nil
 }
 
 func (contraption *Contraption) FindMaxEnergizedTiles() int {
 	maxEnergizedTiles := 0
+	energizedTiles := parallel.Map(contraption.EdgePositions(), contraption.EnergizedTiles)
 
-	for y := 0; y < contraption.DimY; y++ {
-		energizedTiles := contraption.EnergizedTiles(Position{0, y, RIGHT})
-		if energizedTiles > maxEnergizedTiles {
-			maxEnergizedTiles = energizedTiles
+	for _, energizedTilesFromEdge := range energizedTiles {
+		if energizedTilesFromEdge > maxEnergizedTiles {
+			maxEnergizedTiles = energizedTilesFromEdge
 		}
+	}
 
-		energizedTiles = contraption.EnergizedTiles(Position{contraption.DimX - 1, y, LEFT})
-		if energizedTiles > maxEnergizedTiles {
-			maxEnergizedTiles = energizedTiles
-		}
+	return maxEnergizedTiles
+}
+
+// EdgePositions lists all positions from which a beam can enter the contraption
+func (contraption *Contraption) EdgePositions() []Position {
+	positions := []Position{}
+
+	for y := 0; y < contraption.DimY; y++ {
+		positions = append(positions, Position{0, y, RIGHT})
+		positions = append(positions, Position{contraption.DimX - 1, y, LEFT})
 	}
 
 	for x := 0; x < contraption.DimX; x++ {
-		energizedTiles := contraption.EnergizedTiles(Position{x, 0, DOWN})
-		if energizedTiles > maxEnergizedTiles {
-			maxEnergizedTiles = energizedTiles
-		}
-
-		energizedTiles = contraption.EnergizedTiles(Position{x, contraption.DimY - 1, UP})
-		if energizedTiles > maxEnergizedTiles {
-			maxEnergizedTiles = energizedTiles
-		}
+		positions = append(positions, Position{x, 0, DOWN})
+		positions = append(positions, Position{x, contraption.DimY - 1, UP})
 	}
 
-	return maxEnergizedTiles
+	return positions
 }
 
 func (contraption *Contraption) EnergizedTiles(initialPosition Position) int {
-	contraption.Beams = make([][]Direction, contraption.DimY)
+	return contraption.Energize(initialPosition, nil).CountEnergized()
+}
+
+func (contraption *Contraption) NewBeams() Beams {
+	beams := make(Beams, contraption.DimY)
 	for y := 0; y < contraption.DimY; y++ {
-		contraption.Beams[y] = make([]Direction, contraption.DimX)
+		beams[y] = make([]Direction, contraption.DimX)
 	}
+	return beams
+}
 
+// Energize follows the beam from the initial position, when recording it emits
+// a frame every time all positions of the current beam front are processed
+func (contraption *Contraption) Energize(initialPosition Position, recorder *visual.Recorder) Beams {
+	beams := contraption.NewBeams()
 	oldPositions := make(map[Position]bool)
 	analyzedPositions := []Position{initialPosition}
+	positionsLeftInFront, front := 1, 0
 
 	for len(analyzedPositions) > 0 {
+		if recorder.Enabled() && positionsLeftInFront == 0 {
+			front++
+			recorder.Record(contraption.Frame(beams, analyzedPositions, fmt.Sprintf("Beam front %d", front)))
+			positionsLeftInFront = len(analyzedPositions)
+		}
+
 		analyzedPosition := analyzedPositions[0]
 		analyzedPositions = analyzedPositions[1:]
+		positionsLeftInFront--
 
-		nextPositions := contraption.EnergizeTile(analyzedPosition)
+		nextPositions := contraption.EnergizeTile(beams, analyzedPosition)
 		oldPositions[analyzedPosition] = true
 
 		for _, nextPosition := range nextPositions {
@@ -95,10 +143,52 @@ func (contraption *Contraption) EnergizedTiles(initialPosition Position) int {
 		}
 	}
 
-	numEnergizedTiles := 0
+	recorder.Record(contraption.Frame(beams, analyzedPositions, "Energized tiles"))
+	return beams
+}
+
+func (contraption *Contraption) Frame(beams Beams, front []Position, caption string) visual.Frame {
+	frame := visual.NewFrame(contraption.Grid, caption)
+
+	for y := 0; y < contraption.DimY; y++ {
+		for x := 0; x < contraption.DimX; x++ {
+			if beams[y][x] != 0 {
+				frame.SetStyle(x, y, visual.BEAM)
+			}
+		}
+	}
+
+	for _, position := range front {
+		frame.SetStyle(position.X, position.Y, visual.FRONTIER)
+	}
+
+	return frame
+}
+
+// Heatmap styles every tile by the number of beam directions passing through it
+func (contraption *Contraption) Heatmap(beams Beams) visual.Frame {
+	frame := visual.NewFrame(contraption.Grid, "Energized tiles heatmap")
+
 	for y := 0; y < contraption.DimY; y++ {
 		for x := 0; x < contraption.DimX; x++ {
-			if contraption.Beams[y][x] != 0 {
+			directions := 0
+			for _, direction := range []Direction{UP, RIGHT, DOWN, LEFT} {
+				if beams[y][x]&direction != 0 {
+					directions++
+				}
+			}
+			frame.SetStyle(x, y, visual.HEAT_STYLES[directions])
+		}
+	}
+
+	return frame
+}
+
+func (beams Beams) CountEnergized() int {
+	numEnergizedTiles := 0
+	for y := 0; y < len(beams); y++ {
+		for x := 0; x < len(beams[y]); x++ {
+			if beams[y][x] != 0 {
 				numEnergizedTiles++
 			}
 		}
@@ -107,19 +197,13 @@ func (contraption *Contraption) EnergizedTiles(initialPosition Position) int {
 	return numEnergizedTiles
 }
 
-func (contraption *Contraption) EnergizeTile(position Position) []Position {
+func (contraption *Contraption) EnergizeTile(beams Beams, position Position) []Position {
 	tile := contraption.Grid[position.Y][position.X]
 	beamDirections := DetermineBeamDirections(tile, position.EntryDir)
 	nextAnalyzedPositions := []Position{}
 
-	oldBeams := make([][]Direction, contraption.DimY)
-	for y := 0; y < contraption.DimY; y++ {
-		oldBeams[y] = make([]Direction, contraption.DimX)
-		copy(oldBeams[y], contraption.Beams[y])
-	}
-
 	for _, beamDirection := range beamDirections {
-		contraption.Beams[position.Y][position.X] |= beamDirection
+		beams[position.Y][position.X] |= beamDirection
 		neighborPosition, ok := contraption.FindNeighbor(position, beamDirection)
 
 		if ok {
@@ -163,17 +247,23 @@ func (contraption *Contraption) PrintTiles() {
 	fmt.Println()
 }
 
-func (contraption *Contraption) PrintEnergizedTiles() {
+func (contraption *Contraption) PrintEnergizedTiles(beams Beams) {
+	cells := make([][]byte, contraption.DimY)
+	for y := range cells {
+		cells[y] = bytes.Repeat([]byte{'.'}, contraption.DimX)
+	}
+
+	frame := visual.NewFrame(cells, "")
 	for y := 0; y < contraption.DimY; y++ {
 		for x := 0; x < contraption.DimX; x++ {
-			if contraption.Beams[y][x] != 0 {
-				fmt.Print("#")
-			} else {
-				fmt.Print(".")
+			if beams[y][x] != 0 {
+				frame.Cells[y][x] = '#'
+				frame.SetStyle(x, y, visual.BEAM)
 			}
 		}
-		fmt.Println()
 	}
+
+	visual.RenderFrame(os.Stdout, frame)
 	fmt.Println()
 }
 
@@ -265,6 +355,27 @@ func parseInput(lines []string) *Contraption {
 	return &contraption
 }
 
+func Parse(reader io.Reader) (*Contraption, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(lines), nil
+}
+
+func Format(writer io.Writer, contraption *Contraption) error {
+	buffered := bufio.NewWriter(writer)
+	for _, row := range contraption.Grid {
+		buffered.Write(row)
+		buffered.WriteByte('\n')
+	}
+	return buffered.Flush()
+}
+
+func Validate(lines []string) []error {
+	return input.CheckGrid(lines, `.|-/\`, 1)
+}
+
 func readLines(path string) []string {
 	fd, err := os.Open(path)
 	if err != nil {
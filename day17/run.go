@@ -1,17 +1,28 @@
 package day17
 
 import (
+	"aoc2023/export"
+	"aoc2023/input"
+	"aoc2023/visual"
 	"bufio"
+	"embed"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
+	"slices"
 	"strconv"
 
 	pq "gopkg.in/dnaeon/go-priorityqueue.v1"
 )
 
+const TITLE = "Clumsy Crucible"
 const INPUT_FILE_PATH = "day17/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 type Direction int
 
 const (
@@ -47,29 +58,72 @@ func Run() {
 		panic(err)
 	}
 
-	roadmap.Predicate = func(oldStep Step, step Step) bool {
-		return step.Stride < 4
-	}
-	sinkDistancePart1 := roadmap.DijkstraShortestPathLength()
+	roadmap.Predicate = PredicatePart1
+	sinkDistancePart1, path := roadmap.DijkstraShortestPath(visual.Default)
 	fmt.Println("Shortest path distance [PART 1]: ", sinkDistancePart1)
 
-	roadmap.Predicate = func(oldStep Step, step Step) bool {
-		if oldStep.Stride < 4 {
-			return step.Direction == oldStep.Direction
-		}
-		return step.Stride < 11
+	if export.Enabled() {
+		export.WriteGrid("day17", roadmap.PathFrame(path))
 	}
 
-	sinkDistancePart2 := roadmap.DijkstraShortestPathLength()
+	roadmap.Predicate = PredicatePart2
+	sinkDistancePart2 := roadmap.DijkstraShortestPathLength(visual.Default)
 	fmt.Println("Shortest path distance [PART 2]: ", sinkDistancePart2)
 }
 
-func (roadmap *Roadmap) DijkstraShortestPathLength() int64 {
+func Part1(lines []string) (int, error) {
+	roadmap, err := parseInput(lines)
+	if err != nil {
+		return 0, err
+	}
+
+	roadmap.Predicate = PredicatePart1
+	return int(roadmap.DijkstraShortestPathLength(nil)), nil
+}
+
+func Part2(lines []string) (int, error) {
+	roadmap, err := parseInput(lines)
+	if err != nil {
+		return 0, err
+	}
+
+	roadmap.Predicate = PredicatePart2
+	return int(roadmap.DijkstraShortestPathLength(nil)), nil
+}
+
+func PredicatePart1(oldStep Step, step Step) bool {
+	return step.Stride < 4
+}
+
+func PredicatePart2(oldStep Step, step Step) bool {
+	if oldStep.Stride < 4 {
+		return step.Direction == oldStep.Direction
+	}
+	return step.Stride < 11
+}
+
+func (roadmap *Roadmap) DijkstraShortestPathLength(recorder *visual.Recorder) int64 {
+	distance, _ := roadmap.DijkstraShortestPath(recorder)
+	return distance
+}
+
+// DijkstraShortestPath finds the minimal heat loss and the path with it, when recording it
+// emits a frame with the visited positions and the frontier every time the distance grows
+func (roadmap *Roadmap) DijkstraShortestPath(recorder *visual.Recorder) (int64, []Position) {
 	visited := make(map[Step]bool, roadmap.DimY)
 	queue := pq.New[Step, int64](pq.MinHeap)
+	visitedPositions := make(map[Position]bool)
+	frontier := []Position{}
+	frontierDistance := int64(0)
+
+	// remember the step each step was reached from with the best distance so far
+	bestDistances := make(map[Step]int64)
+	previousSteps := make(map[Step]Step)
 
-	queue.Put(Step{Position{1, 0}, RIGHT, 1}, roadmap.Grid[0][1])
-	queue.Put(Step{Position{0, 1}, DOWN, 1}, roadmap.Grid[1][0])
+	for _, step := range []Step{{Position{1, 0}, RIGHT, 1}, {Position{0, 1}, DOWN, 1}} {
+		bestDistances[step] = roadmap.Grid[step.Position.Y][step.Position.X]
+		queue.Put(step, bestDistances[step])
+	}
 
 	for !queue.IsEmpty() {
 		item := queue.Get()
@@ -81,16 +135,75 @@ func (roadmap *Roadmap) DijkstraShortestPathLength() int64 {
 			continue
 		}
 
+		if recorder.Enabled() {
+			if distance > frontierDistance {
+				caption := fmt.Sprintf("Dijkstra frontier at distance %d", frontierDistance)
+				recorder.Record(roadmap.Frame(visitedPositions, frontier, caption))
+				frontier, frontierDistance = []Position{}, distance
+			}
+			visitedPositions[step.Position] = true
+			frontier = append(frontier, step.Position)
+		}
+
 		if roadmap.EndingConditionMet(step, step) {
-			return distance
+			if recorder.Enabled() {
+				caption := fmt.Sprintf("Reached the factory at distance %d", distance)
+				recorder.Record(roadmap.Frame(visitedPositions, frontier, caption))
+			}
+			return distance, reconstructPath(previousSteps, step)
 		}
 
 		for _, nextStep := range roadmap.AllowedSteps(step) {
-			queue.Put(nextStep, roadmap.IncDistance(nextStep, distance))
+			nextDistance := roadmap.IncDistance(nextStep, distance)
+			if bestDistance, ok := bestDistances[nextStep]; !ok || nextDistance < bestDistance {
+				bestDistances[nextStep] = nextDistance
+				previousSteps[nextStep] = step
+			}
+			queue.Put(nextStep, nextDistance)
+		}
+	}
+
+	return math.MaxInt64, nil
+}
+
+func reconstructPath(previousSteps map[Step]Step, lastStep Step) []Position {
+	path := []Position{lastStep.Position}
+
+	for step, ok := previousSteps[lastStep]; ok; step, ok = previousSteps[step] {
+		path = append(path, step.Position)
+	}
+	path = append(path, Position{0, 0})
+
+	slices.Reverse(path)
+	return path
+}
+
+func (roadmap *Roadmap) PathFrame(path []Position) visual.Frame {
+	frame := roadmap.Frame(map[Position]bool{}, []Position{}, "Shortest path")
+	for _, position := range path {
+		frame.SetStyle(position.X, position.Y, visual.PATH)
+	}
+	return frame
+}
+
+func (roadmap *Roadmap) Frame(visited map[Position]bool, frontier []Position, caption string) visual.Frame {
+	cells := make([][]byte, roadmap.DimY)
+	for y := 0; y < roadmap.DimY; y++ {
+		cells[y] = make([]byte, roadmap.DimX)
+		for x := 0; x < roadmap.DimX; x++ {
+			cells[y][x] = byte('0' + roadmap.Grid[y][x])
 		}
 	}
 
-	return math.MaxInt64
+	frame := visual.NewFrame(cells, caption)
+	for position := range visited {
+		frame.SetStyle(position.X, position.Y, visual.VISITED)
+	}
+	for _, position := range frontier {
+		frame.SetStyle(position.X, position.Y, visual.FRONTIER)
+	}
+
+	return frame
 }
 
 func (roadmap *Roadmap) EndingConditionMet(oldStep Step, step Step) bool {
@@ -261,6 +374,33 @@ func oppositeDirection(direction Direction) Direction {
 	return 0
 }
 
+func Parse(reader io.Reader) (*Roadmap, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("empty roadmap")
+	}
+	return parseInput(lines)
+}
+
+// Format writes the heat losses of the roadmap, the predicate is not a part of the text
+func Format(writer io.Writer, roadmap *Roadmap) error {
+	buffered := bufio.NewWriter(writer)
+	for _, row := range roadmap.Grid {
+		for _, heatLoss := range row {
+			fmt.Fprint(buffered, heatLoss)
+		}
+		buffered.WriteByte('\n')
+	}
+	return buffered.Flush()
+}
+
+func Validate(lines []string) []error {
+	return input.CheckGrid(lines, "0123456789", 1)
+}
+
 func readLines(path string) []string {
 	fd, err := os.Open(path)
 	if err != nil {
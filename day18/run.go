@@ -1,15 +1,25 @@
 package day18
 
 import (
+	"aoc2023/export"
+	"aoc2023/input"
 	"bufio"
+	"embed"
 	"fmt"
+	"image"
+	"image/color"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 )
 
+const TITLE = "Lavaduct Lagoon"
 const INPUT_FILE_PATH = "day18/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 type Direction int
 
 const (
@@ -23,6 +33,7 @@ const (
 type Edge struct {
 	Direction Direction
 	Length    int
+	Color     color.RGBA
 }
 
 func Run() {
@@ -35,6 +46,15 @@ func Run() {
 	areaSize := CalculateArea(edges)
 	fmt.Println("Area size [PART 1]: ", areaSize)
 
+	if export.Enabled() {
+		// only the drawing needs the colours
+		coloredEdges, err := parseInput(lines, parseLineWithColor)
+		if err != nil {
+			panic(err)
+		}
+		export.WritePolygon("day18", Polygon(coloredEdges))
+	}
+
 	edges, err = parseInput(lines, parseLinePart2)
 	if err != nil {
 		panic(err)
@@ -44,6 +64,22 @@ func Run() {
 	fmt.Println("Area size [PART 2]: ", areaSize)
 }
 
+func Part1(lines []string) (int, error) {
+	edges, err := parseInput(lines, parseLinePart1)
+	if err != nil {
+		return 0, err
+	}
+	return CalculateArea(edges), nil
+}
+
+func Part2(lines []string) (int, error) {
+	edges, err := parseInput(lines, parseLinePart2)
+	if err != nil {
+		return 0, err
+	}
+	return CalculateArea(edges), nil
+}
+
 func CalculateArea(edges []Edge) int {
 	_, offsetX, _, offsetY := AccessMaxDims(edges)
 	positionX, positionY := offsetX, offsetY
@@ -70,6 +106,30 @@ func CalculateArea(edges []Edge) int {
 	return area + edgeLengths/2 + 1
 }
 
+// Polygon walks the edges from the origin, every edge keeps the colour of its trench
+func Polygon(edges []Edge) export.Polygon {
+	polygon := export.Polygon{}
+	position := image.Point{}
+
+	for _, edge := range edges {
+		polygon.Points = append(polygon.Points, position)
+		polygon.Colors = append(polygon.Colors, edge.Color)
+
+		switch edge.Direction {
+		case RIGHT:
+			position.X += edge.Length
+		case LEFT:
+			position.X -= edge.Length
+		case DOWN:
+			position.Y += edge.Length
+		case UP:
+			position.Y -= edge.Length
+		}
+	}
+
+	return polygon
+}
+
 func AccessMaxDims(edges []Edge) (int, int, int, int) {
 	maxDimX, maxDimY := 0, 0
 	minDimX, minDimY := 0, 0
@@ -119,7 +179,17 @@ func parseInput(lines []string, lineParser func(string) (*Edge, error)) ([]Edge,
 	return edges, nil
 }
 
+// the colour is only drawn, the area of part 1 does not need it
 func parseLinePart1(line string) (*Edge, error) {
+	return parseEdge(line, false)
+}
+
+// parseLineWithColor parses the line as in part 1 along with the colour, which is required
+func parseLineWithColor(line string) (*Edge, error) {
+	return parseEdge(line, true)
+}
+
+func parseEdge(line string, withColor bool) (*Edge, error) {
 	var edge Edge
 
 	tokens := strings.Split(line, " ")
@@ -146,9 +216,29 @@ func parseLinePart1(line string) (*Edge, error) {
 	}
 	edge.Length = length
 
+	if withColor {
+		edgeColor, err := parseColor(tokens[2])
+		if err != nil {
+			return nil, err
+		}
+		edge.Color = edgeColor
+	}
+
 	return &edge, nil
 }
 
+func parseColor(token string) (color.RGBA, error) {
+	analyzedString := strings.TrimPrefix(token, "(#")
+	analyzedString = strings.TrimSuffix(analyzedString, ")")
+
+	number, err := strconv.ParseUint(analyzedString, 16, 32)
+	if err != nil || len(analyzedString) != 6 {
+		return color.RGBA{}, fmt.Errorf("Invalid color: %s", token)
+	}
+
+	return color.RGBA{uint8(number >> 16), uint8(number >> 8), uint8(number), 0xff}, nil
+}
+
 func parseLinePart2(line string) (*Edge, error) {
 	var edge Edge
 
@@ -159,6 +249,9 @@ func parseLinePart2(line string) (*Edge, error) {
 
 	analyzedString := strings.TrimPrefix(tokens[2], "(#")
 	analyzedString = strings.TrimSuffix(analyzedString, ")")
+	if len(analyzedString) != 6 {
+		return nil, fmt.Errorf("Invalid color: %s", tokens[2])
+	}
 
 	number, err := strconv.ParseUint(analyzedString[:5], 16, 32)
 	if err != nil {
@@ -182,6 +275,27 @@ func parseLinePart2(line string) (*Edge, error) {
 	return &edge, nil
 }
 
+// Parse reads the dig plan as in part 1, the colors are kept on the edges
+func Parse(reader io.Reader) ([]Edge, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(lines, parseLineWithColor)
+}
+
+func Format(writer io.Writer, edges []Edge) error {
+	buffered := bufio.NewWriter(writer)
+	directionLetters := map[Direction]string{UP: "U", RIGHT: "R", DOWN: "D", LEFT: "L"}
+
+	for _, edge := range edges {
+		fmt.Fprintf(buffered, "%s %d (#%02x%02x%02x)\n",
+			directionLetters[edge.Direction], edge.Length, edge.Color.R, edge.Color.G, edge.Color.B)
+	}
+
+	return buffered.Flush()
+}
+
 func readLines(path string) []string {
 	fd, err := os.Open(path)
 	if err != nil {
@@ -1,12 +1,17 @@
 package day19
 
 import (
+	"aoc2023/input"
+	"aoc2023/trace"
 	"bufio"
+	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -48,15 +53,36 @@ type Interval struct {
 type Range map[Category]Interval
 type Results map[string][]Range
 
+const TITLE = "Aplenty"
 const INPUT_FILE_PATH = "day19/input.txt"
 
+//go:embed samples
+var Samples embed.FS
+
 func Run() {
 	inputLines := readLines(INPUT_FILE_PATH)
-	system, parts, err := parseInput(inputLines)
+
+	sumOfRatings, err := Part1(inputLines)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Sum of ratings [PART 1]: ", sumOfRatings)
+
+	totalCombinations, err := Part2(inputLines)
 	if err != nil {
 		panic(err)
 	}
 
+	fmt.Println("Total combinations [PART 2]: ", totalCombinations)
+}
+
+func Part1(inputLines []string) (int, error) {
+	system, parts, err := parseInput(inputLines)
+	if err != nil {
+		return 0, err
+	}
+
 	sumOfRatings := 0
 	for _, part := range parts {
 		if system.EvaluatePart(part) {
@@ -64,7 +90,14 @@ func Run() {
 		}
 	}
 
-	fmt.Println("Sum of ratings [PART 1]: ", sumOfRatings)
+	return sumOfRatings, nil
+}
+
+func Part2(inputLines []string) (int, error) {
+	system, _, err := parseInput(inputLines)
+	if err != nil {
+		return 0, err
+	}
 
 	cachedRanges := make(Results)
 	acceptedRanges := system.EvaluateRange(cachedRanges, "in")
@@ -74,7 +107,7 @@ func Run() {
 		totalCombinations += acceptedRange.Combinations()
 	}
 
-	fmt.Println("Total combinations [PART 2]: ", totalCombinations)
+	return totalCombinations, nil
 }
 
 func (range_ Range) String() string {
@@ -257,11 +290,17 @@ func Intersection(r1 Interval, r2 Interval) (bool, Interval) {
 }
 
 func (system System) EvaluatePart(part Part) bool {
+	return system.TracedEvaluatePart(part, nil)
+}
+
+// TracedEvaluatePart evaluates the part like EvaluatePart and traces
+// the workflows it goes through with the rules that sent it further
+func (system System) TracedEvaluatePart(part Part, tracer *trace.Tracer) bool {
 	workflowName := "in"
 
 	for {
 		workflow := system[workflowName]
-		workflowName = workflow.EvaluatePart(part)
+		workflowName = workflow.TracedEvaluatePart(part, tracer)
 		if workflowName == "ACCEPT" {
 			return true
 		}
@@ -272,15 +311,30 @@ func (system System) EvaluatePart(part Part) bool {
 }
 
 func (workflow *Workflow) EvaluatePart(part Part) string {
+	return workflow.TracedEvaluatePart(part, nil)
+}
+
+func (workflow *Workflow) TracedEvaluatePart(part Part, tracer *trace.Tracer) string {
 	for _, condition := range workflow.Conditions {
 		effect := condition.EvaluatePart(part)
 		if effect != "" {
+			if tracer.Enabled() {
+				tracer.Emit("workflow", trace.Fields{"name": workflow.Name, "rule": condition.String(), "next": effect})
+			}
 			return effect
 		}
 	}
+
+	if tracer.Enabled() {
+		tracer.Emit("workflow", trace.Fields{"name": workflow.Name, "rule": "fallback", "next": workflow.LastEffect})
+	}
 	return workflow.LastEffect
 }
 
+func (condition Condition) String() string {
+	return fmt.Sprintf("%s%s%d", categoryChar(condition.Category), condition.Operator, condition.Number)
+}
+
 func (condition *Condition) EvaluatePart(part Part) string {
 	partValue := part[condition.Category]
 	switch condition.Operator {
@@ -296,6 +350,95 @@ func (condition *Condition) EvaluatePart(part Part) string {
 	return ""
 }
 
+// Explain traces the path through the workflows of the part with the given number (from 1)
+func Explain(inputLines []string, partNumber int, tracer *trace.Tracer) error {
+	system, parts, err := parseInput(inputLines)
+	if err != nil {
+		return err
+	}
+	if partNumber < 1 || partNumber > len(parts) {
+		return fmt.Errorf("no part %d, there are %d parts", partNumber, len(parts))
+	}
+
+	part := parts[partNumber-1]
+	tracer.Emit("part", trace.Fields{"x": part[X], "m": part[M], "a": part[A], "s": part[S]})
+
+	accepted := system.TracedEvaluatePart(part, tracer)
+	rating := 0
+	if accepted {
+		rating = part.TotalRating()
+	}
+
+	tracer.Emit("result", trace.Fields{"accepted": accepted, "rating": rating})
+	return nil
+}
+
+// Validate parses every line on its own and checks that the workflows
+// start at "in", every target exists and every part has all the ratings
+func Validate(inputLines []string) []error {
+	var problems []error
+	workflowLines := make(map[string]int)
+	var workflows []*Workflow
+	var lineNumbers []int
+	i := 0
+
+	for ; i < len(inputLines) && inputLines[i] != ""; i++ {
+		workflow, err := parseWorkflow(inputLines[i])
+		if err != nil {
+			problems = append(problems, fmt.Errorf("line %d: %w", i+1, err))
+			continue
+		}
+
+		if firstLine, ok := workflowLines[workflow.Name]; ok {
+			problems = append(problems, fmt.Errorf("line %d: workflow %s is already defined on line %d", i+1, workflow.Name, firstLine))
+		} else {
+			workflowLines[workflow.Name] = i + 1
+		}
+		workflows = append(workflows, workflow)
+		lineNumbers = append(lineNumbers, i+1)
+	}
+
+	if _, ok := workflowLines["in"]; !ok {
+		problems = append(problems, errors.New("no workflow named in"))
+	}
+
+	for j, workflow := range workflows {
+		targets := []string{workflow.LastEffect}
+		for _, condition := range workflow.Conditions {
+			targets = append(targets, condition.Effect)
+		}
+
+		for _, target := range targets {
+			if _, ok := workflowLines[target]; !ok && target != "ACCEPT" && target != "REJECT" {
+				problems = append(problems, fmt.Errorf("line %d: workflow %s sends parts to undefined workflow %s",
+					lineNumbers[j], workflow.Name, target))
+			}
+		}
+	}
+
+	parts := 0
+	for ; i < len(inputLines); i++ {
+		if inputLines[i] == "" {
+			continue
+		}
+
+		part, err := parsePart(inputLines[i])
+		if err != nil {
+			problems = append(problems, fmt.Errorf("line %d: %w", i+1, err))
+			continue
+		}
+		if len(*part) != 4 {
+			problems = append(problems, fmt.Errorf("line %d: part has %d ratings, expected x, m, a and s", i+1, len(*part)))
+		}
+		parts++
+	}
+
+	if parts == 0 {
+		problems = append(problems, errors.New("no parts"))
+	}
+	return problems
+}
+
 func parseInput(lines []string) (*System, []Part, error) {
 	system := make(System)
 	parts := []Part{}
@@ -429,6 +572,60 @@ func stringToCategory(categoryString string) (Category, error) {
 	}
 }
 
+func Parse(reader io.Reader) (*System, []Part, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseInput(lines)
+}
+
+// Format writes the workflows sorted by name, an empty line and the parts
+func Format(writer io.Writer, system *System, parts []Part) error {
+	buffered := bufio.NewWriter(writer)
+
+	names := make([]string, 0, len(*system))
+	for name := range *system {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		workflow := (*system)[name]
+		clauses := []string{}
+		for _, condition := range workflow.Conditions {
+			clauses = append(clauses, condition.String()+":"+effectString(condition.Effect))
+		}
+		clauses = append(clauses, effectString(workflow.LastEffect))
+
+		fmt.Fprintf(buffered, "%s{%s}\n", name, strings.Join(clauses, ","))
+	}
+
+	buffered.WriteByte('\n')
+	for _, part := range parts {
+		ratings := []string{}
+		for category := X; category <= S; category++ {
+			if rating, ok := part[category]; ok {
+				ratings = append(ratings, fmt.Sprintf("%s=%d", categoryChar(category), rating))
+			}
+		}
+
+		fmt.Fprintf(buffered, "{%s}\n", strings.Join(ratings, ","))
+	}
+
+	return buffered.Flush()
+}
+
+func effectString(effect string) string {
+	switch effect {
+	case "ACCEPT":
+		return "A"
+	case "REJECT":
+		return "R"
+	}
+	return effect
+}
+
 func readLines(path string) []string {
 	fd, err := os.Open(path)
 	if err != nil {
@@ -0,0 +1,26 @@
+package day19
+
+import (
+	"aoc2023/sampletest"
+	"io"
+	"testing"
+)
+
+// the workflows and the parts are separate models, the round trip compares both
+type model struct {
+	System *System
+	Parts  []Part
+}
+
+func parseModel(reader io.Reader) (model, error) {
+	system, parts, err := Parse(reader)
+	return model{system, parts}, err
+}
+
+func formatModel(writer io.Writer, parsed model) error {
+	return Format(writer, parsed.System, parsed.Parts)
+}
+
+func TestParseFormatRoundTrip(t *testing.T) {
+	sampletest.RoundTrip(t, Samples, parseModel, formatModel, Part1, Part2)
+}
@@ -1,15 +1,24 @@
 package day20
 
 import (
+	"aoc2023/input"
 	"bufio"
+	"embed"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"golang.org/x/exp/maps"
 )
 
+const TITLE = "Pulse Propagation"
 const INPUT_FILE_PATH = "day20/input.txt"
+
+//go:embed samples
+var Samples embed.FS
+
 const ITERATIONS = 1000
 
 const (
@@ -69,19 +78,37 @@ type State struct {
 func Run() {
 	inputLines := readLines(INPUT_FILE_PATH)
 
-	machinery, err := parseInput(inputLines)
+	multiplicationPart1, err := Part1(inputLines)
 	if err != nil {
 		panic(err)
 	}
 
-	fmt.Println("Multiplication [PART 1]: ", machinery.PushButtonNTimesAndMultiplyPulseCounts(ITERATIONS))
+	fmt.Println("Multiplication [PART 1]: ", multiplicationPart1)
 
-	machinery, err = parseInput(inputLines)
+	multiplicationPart2, err := Part2(inputLines)
 	if err != nil {
 		panic(err)
 	}
 
-	fmt.Println("Multiplication [PART 2]: ", machinery.SplitIntoSubmachineriesAndFindCommonPeriod())
+	fmt.Println("Multiplication [PART 2]: ", multiplicationPart2)
+}
+
+func Part1(inputLines []string) (int, error) {
+	machinery, err := parseInput(inputLines)
+	if err != nil {
+		return 0, err
+	}
+
+	return machinery.PushButtonNTimesAndMultiplyPulseCounts(ITERATIONS), nil
+}
+
+func Part2(inputLines []string) (int, error) {
+	machinery, err := parseInput(inputLines)
+	if err != nil {
+		return 0, err
+	}
+
+	return machinery.SplitIntoSubmachineriesAndFindCommonPeriod(), nil
 }
 
 func (machinery *Machinery) PushButtonNTimesAndMultiplyPulseCounts(n int) int {
@@ -384,6 +411,10 @@ func parseInput(lines []string) (*Machinery, error) {
 		}
 
 		parts := strings.Split(line, " -> ")
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid module: %s", line)
+		}
+
 		rawOutputs := strings.Split(parts[1], ", ")
 		outputs := []string{}
 
@@ -432,6 +463,102 @@ func parseInput(lines []string) (*Machinery, error) {
 	return &machinery, nil
 }
 
+func Parse(reader io.Reader) (*Machinery, error) {
+	lines, err := input.Lines(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(lines)
+}
+
+// Format writes the configuration of the modules sorted by name,
+// the states of the modules and the sinks are not a part of the text
+func Format(writer io.Writer, machinery *Machinery) error {
+	buffered := bufio.NewWriter(writer)
+
+	names := maps.Keys(machinery.Modules)
+	sort.Strings(names)
+
+	for _, name := range names {
+		module := machinery.Modules[name]
+
+		var prefix string
+		switch module.(type) {
+		case *FlipFlop:
+			prefix = "%"
+		case *Conjunction:
+			prefix = "&"
+		case *Broadcaster:
+			prefix = ""
+		default:
+			continue
+		}
+
+		fmt.Fprintf(buffered, "%s%s -> %s\n", prefix, name, strings.Join(module.GetOutputs(), ", "))
+	}
+
+	return buffered.Flush()
+}
+
+// Validate checks every module line, that the broadcaster exists and that the outputs
+// reference defined modules, except for a single sink like rx that only receives pulses
+func Validate(lines []string) []error {
+	var problems []error
+	moduleLines := make(map[string]int)
+	outputLines := make(map[string]int)
+
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		parts := strings.Split(line, " -> ")
+		if len(parts) != 2 || strings.TrimLeft(parts[0], "%&") == "" {
+			problems = append(problems, fmt.Errorf("line %d: expected <module> -> <outputs>: %s", i+1, line))
+			continue
+		}
+
+		name := strings.TrimLeft(parts[0], "%&")
+		if firstLine, ok := moduleLines[name]; ok {
+			problems = append(problems, fmt.Errorf("line %d: module %s is already defined on line %d", i+1, name, firstLine))
+		}
+		moduleLines[name] = i + 1
+
+		if parts[0] == name && name != "broadcaster" {
+			problems = append(problems, fmt.Errorf("line %d: module %s is neither a flip-flop (%%) nor a conjunction (&)", i+1, name))
+		}
+
+		for _, output := range strings.Split(parts[1], ", ") {
+			if output == "" {
+				problems = append(problems, fmt.Errorf("line %d: module %s has an empty output", i+1, name))
+			} else if _, ok := outputLines[output]; !ok {
+				outputLines[output] = i + 1
+			}
+		}
+	}
+
+	if _, ok := moduleLines["broadcaster"]; !ok {
+		problems = append(problems, fmt.Errorf("no broadcaster module"))
+	}
+
+	var sinks []string
+	for output := range outputLines {
+		if _, ok := moduleLines[output]; !ok {
+			sinks = append(sinks, output)
+		}
+	}
+	sort.Strings(sinks)
+
+	if len(sinks) > 1 {
+		for _, sink := range sinks {
+			problems = append(problems, fmt.Errorf("line %d: output %s is not a defined module, only a single sink is expected (found %s)",
+				outputLines[sink], sink, strings.Join(sinks, ", ")))
+		}
+	}
+
+	return problems
+}
+
 func readLines(path string) []string {
 	fd, err := os.Open(path)
 	if err != nil {
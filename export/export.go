@@ -0,0 +1,91 @@
+package export
+
+import (
+	"aoc2023/visual"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+// Directory the pictures are written to, rendering is disabled when empty
+var Directory string
+
+// Size of a single grid cell in pixels
+var Scale = 4
+
+// errors of the pictures written so far, the days cannot return them, see Err
+var failures []error
+
+var BACKGROUND = color.RGBA{0x10, 0x10, 0x18, 0xff}
+
+// Polygon is a closed polygon with a colour for every edge,
+// edge i goes from Points[i] to Points[i+1]
+type Polygon struct {
+	Points []image.Point
+	Colors []color.RGBA
+}
+
+func Enabled() bool {
+	return Directory != ""
+}
+
+// Err returns the errors of writing the pictures, nil when all of them were written
+func Err() error {
+	return errors.Join(failures...)
+}
+
+// WriteGrid writes the frame as <name>.png and <name>.svg to the Directory,
+// a failure is kept for Err
+func WriteGrid(name string, frame visual.Frame) {
+	if err := writePNG(name, GridImage(frame)); err != nil {
+		failures = append(failures, err)
+		return
+	}
+	if err := writeFile(name+".svg", GridSVG(frame)); err != nil {
+		failures = append(failures, err)
+	}
+}
+
+// WritePolygon writes the polygon as <name>.png and <name>.svg to the Directory,
+// a failure is kept for Err
+func WritePolygon(name string, polygon Polygon) {
+	if err := writePNG(name, PolygonImage(polygon)); err != nil {
+		failures = append(failures, err)
+		return
+	}
+	if err := writeFile(name+".svg", PolygonSVG(polygon)); err != nil {
+		failures = append(failures, err)
+	}
+}
+
+func writePNG(name string, img image.Image) error {
+	if err := os.MkdirAll(Directory, 0755); err != nil {
+		return err
+	}
+
+	fd, err := os.Create(filepath.Join(Directory, name+".png"))
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	if err := png.Encode(fd, img); err != nil {
+		return fmt.Errorf("cannot encode %s.png: %w", name, err)
+	}
+	return nil
+}
+
+func writeFile(fileName string, content string) error {
+	if err := os.MkdirAll(Directory, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(Directory, fileName), []byte(content), 0644)
+}
+
+func hex(rgba color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", rgba.R, rgba.G, rgba.B)
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"aoc2023/visual"
+	"fmt"
+	"image"
+	"image/draw"
+	"strings"
+)
+
+func GridImage(frame visual.Frame) *image.RGBA {
+	dimX, dimY := frameDims(frame)
+	img := image.NewRGBA(image.Rect(0, 0, dimX*Scale, dimY*Scale))
+	draw.Draw(img, img.Bounds(), image.NewUniform(BACKGROUND), image.Point{}, draw.Src)
+
+	for y, row := range frame.Styles {
+		for x, style := range row {
+			cell := image.Rect(x*Scale, y*Scale, (x+1)*Scale, (y+1)*Scale)
+			draw.Draw(img, cell, image.NewUniform(visual.PALETTE[style].RGBA), image.Point{}, draw.Src)
+		}
+	}
+
+	return img
+}
+
+// GridSVG draws every cell as a rectangle, equally styled cells next to
+// each other in a row are merged to keep the file small
+func GridSVG(frame visual.Frame) string {
+	var svg strings.Builder
+	dimX, dimY := frameDims(frame)
+
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d">`+"\n",
+		dimX*Scale, dimY*Scale)
+	fmt.Fprintf(&svg, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(BACKGROUND))
+
+	for y, row := range frame.Styles {
+		for x := 0; x < len(row); {
+			end := x
+			for end < len(row) && row[end] == row[x] {
+				end++
+			}
+
+			fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+				x*Scale, y*Scale, (end-x)*Scale, Scale, hex(visual.PALETTE[row[x]].RGBA))
+			x = end
+		}
+	}
+
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+func frameDims(frame visual.Frame) (int, int) {
+	dimX := 0
+	for _, row := range frame.Styles {
+		if len(row) > dimX {
+			dimX = len(row)
+		}
+	}
+	return dimX, len(frame.Styles)
+}
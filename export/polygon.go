@@ -0,0 +1,112 @@
+package export
+
+import (
+	"aoc2023/visual"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sort"
+	"strings"
+)
+
+// pixel returns the centre of the cell the point lies in, after moving
+// the polygon so its top left corner is at the origin
+func pixel(point image.Point, topLeft image.Point) (float64, float64) {
+	x := float64((point.X-topLeft.X)*Scale) + float64(Scale)/2
+	y := float64((point.Y-topLeft.Y)*Scale) + float64(Scale)/2
+	return x, y
+}
+
+func (polygon Polygon) Bounds() (image.Point, image.Point) {
+	if len(polygon.Points) == 0 {
+		return image.Point{}, image.Point{}
+	}
+
+	topLeft, bottomRight := polygon.Points[0], polygon.Points[0]
+	for _, point := range polygon.Points {
+		topLeft.X, topLeft.Y = min(topLeft.X, point.X), min(topLeft.Y, point.Y)
+		bottomRight.X, bottomRight.Y = max(bottomRight.X, point.X), max(bottomRight.Y, point.Y)
+	}
+	return topLeft, bottomRight
+}
+
+func (polygon Polygon) edge(i int) (image.Point, image.Point, color.RGBA) {
+	edgeColor := visual.PALETTE[visual.PATH].RGBA
+	if i < len(polygon.Colors) {
+		edgeColor = polygon.Colors[i]
+	}
+	return polygon.Points[i], polygon.Points[(i+1)%len(polygon.Points)], edgeColor
+}
+
+// PolygonImage fills the inside of the polygon scanline by scanline
+// and then draws every edge with its own colour, one cell wide
+func PolygonImage(polygon Polygon) *image.RGBA {
+	topLeft, bottomRight := polygon.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, (bottomRight.X-topLeft.X+1)*Scale, (bottomRight.Y-topLeft.Y+1)*Scale))
+	draw.Draw(img, img.Bounds(), image.NewUniform(BACKGROUND), image.Point{}, draw.Src)
+	inside := visual.PALETTE[visual.INSIDE].RGBA
+
+	for py := 0; py < img.Bounds().Dy(); py++ {
+		scanY := float64(py) + 0.5
+		crossings := []float64{}
+
+		for i := range polygon.Points {
+			from, to, _ := polygon.edge(i)
+			x1, y1 := pixel(from, topLeft)
+			x2, y2 := pixel(to, topLeft)
+
+			if y1 == y2 || scanY < min(y1, y2) || scanY >= max(y1, y2) {
+				continue
+			}
+			crossings = append(crossings, x1+(scanY-y1)*(x2-x1)/(y2-y1))
+		}
+
+		sort.Float64s(crossings)
+		for i := 0; i+1 < len(crossings); i += 2 {
+			for px := int(crossings[i]); px < int(crossings[i+1]); px++ {
+				img.SetRGBA(px, py, inside)
+			}
+		}
+	}
+
+	for i := range polygon.Points {
+		from, to, edgeColor := polygon.edge(i)
+		trench := image.Rect(
+			(min(from.X, to.X)-topLeft.X)*Scale, (min(from.Y, to.Y)-topLeft.Y)*Scale,
+			(max(from.X, to.X)-topLeft.X+1)*Scale, (max(from.Y, to.Y)-topLeft.Y+1)*Scale,
+		)
+		draw.Draw(img, trench, image.NewUniform(edgeColor), image.Point{}, draw.Src)
+	}
+
+	return img
+}
+
+func PolygonSVG(polygon Polygon) string {
+	var svg strings.Builder
+	topLeft, bottomRight := polygon.Bounds()
+
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d">`+"\n",
+		(bottomRight.X-topLeft.X+1)*Scale, (bottomRight.Y-topLeft.Y+1)*Scale)
+	fmt.Fprintf(&svg, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(BACKGROUND))
+
+	points := []string{}
+	for _, point := range polygon.Points {
+		x, y := pixel(point, topLeft)
+		points = append(points, fmt.Sprintf("%g,%g", x, y))
+	}
+	fmt.Fprintf(&svg, `<polygon points="%s" fill="%s"/>`+"\n",
+		strings.Join(points, " "), hex(visual.PALETTE[visual.INSIDE].RGBA))
+
+	for i := range polygon.Points {
+		from, to, edgeColor := polygon.edge(i)
+		x1, y1 := pixel(from, topLeft)
+		x2, y2 := pixel(to, topLeft)
+
+		fmt.Fprintf(&svg, `<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="%s" stroke-width="%d" stroke-linecap="square"/>`+"\n",
+			x1, y1, x2, y2, hex(edgeColor), Scale)
+	}
+
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
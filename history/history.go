@@ -0,0 +1,126 @@
+package history
+
+import (
+	"aoc2023/runner"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const FILE_NAME = "history.jsonl"
+
+// DIRTY_SUFFIX marks the commits run with uncommitted changes
+const DIRTY_SUFFIX = "-dirty"
+
+// Record is a single run of a part, the history file has one record per line
+type Record struct {
+	Time           time.Time     `json:"time"`
+	Commit         string        `json:"commit"`
+	Variant        string        `json:"variant"`
+	Day            int           `json:"day"`
+	Part           int           `json:"part"`
+	Answer         int           `json:"answer"`
+	Duration       time.Duration `json:"duration"`
+	Allocations    uint64        `json:"allocations"`
+	AllocatedBytes uint64        `json:"allocated_bytes"`
+	Error          string        `json:"error,omitempty"`
+}
+
+// DefaultPath keeps the history out of the repository, in the user's cache directory
+func DefaultPath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return FILE_NAME
+	}
+	return filepath.Join(cacheDir, "aoc2023", FILE_NAME)
+}
+
+func NewRecord(commit string, variant string, result runner.Result) Record {
+	record := Record{
+		Time:           time.Now(),
+		Commit:         commit,
+		Variant:        variant,
+		Day:            result.Day,
+		Part:           result.Part,
+		Answer:         result.Answer,
+		Duration:       result.Duration,
+		Allocations:    result.Allocations,
+		AllocatedBytes: result.AllocatedBytes,
+	}
+	if result.Err != nil {
+		record.Error = result.Err.Error()
+	}
+	return record
+}
+
+func Append(path string, records ...Record) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	fd, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	encoder := json.NewEncoder(fd)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read loads all the records in the order they were appended, a missing file is an empty history
+func Read(path string) ([]Record, error) {
+	fd, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(fd)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid history record %s:%d: %w", path, lineNumber, err)
+		}
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
+
+// Commit describes the checked out commit, DIRTY_SUFFIX marks uncommitted changes
+func Commit() string {
+	output, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return "unknown"
+	}
+	commit := strings.TrimSpace(string(output))
+
+	status, err := exec.Command("git", "status", "--porcelain", "--untracked-files=no").Output()
+	if err == nil && len(bytes.TrimSpace(status)) > 0 {
+		commit += DIRTY_SUFFIX
+	}
+	return commit
+}
@@ -0,0 +1,120 @@
+package history
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Point sums up the runs of a part at a single commit
+type Point struct {
+	Commit      string
+	Time        time.Time
+	Runs        int
+	Duration    time.Duration
+	Allocations uint64
+	Answer      int
+
+	// relative changes against the previous commit, 0.25 is 25% more
+	DurationChange    float64
+	AllocationsChange float64
+	AnswerChanged     bool
+	Regression        bool
+}
+
+type Trend struct {
+	Day     int
+	Part    int
+	Variant string
+	Points  []Point
+}
+
+type trendKey struct {
+	Day     int
+	Part    int
+	Variant string
+}
+
+// Trends groups the successful runs by day, part and input variant, the commits in
+// the order of their first run. The fastest run of a commit is its duration, so a
+// single run slowed down by a busy machine does not count as a regression.
+func Trends(records []Record, threshold float64) []Trend {
+	trends := make(map[trendKey]*Trend)
+
+	for _, record := range records {
+		if record.Error != "" {
+			continue
+		}
+
+		key := trendKey{record.Day, record.Part, record.Variant}
+		trend, ok := trends[key]
+		if !ok {
+			trend = &Trend{Day: record.Day, Part: record.Part, Variant: record.Variant}
+			trends[key] = trend
+		}
+		trend.add(record)
+	}
+
+	result := []Trend{}
+	for _, trend := range trends {
+		trend.compare(threshold)
+		result = append(result, *trend)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Day != result[j].Day {
+			return result[i].Day < result[j].Day
+		}
+		if result[i].Part != result[j].Part {
+			return result[i].Part < result[j].Part
+		}
+		return result[i].Variant < result[j].Variant
+	})
+	return result
+}
+
+// the runs of a commit are merged into a single point, except for the dirty ones,
+// the uncommitted changes may differ between them
+func (trend *Trend) add(record Record) {
+	for i := range trend.Points {
+		point := &trend.Points[i]
+		if point.Commit == record.Commit && !strings.HasSuffix(record.Commit, DIRTY_SUFFIX) {
+			point.Runs++
+			point.Duration = min(point.Duration, record.Duration)
+			point.Allocations = min(point.Allocations, record.Allocations)
+			point.Answer = record.Answer
+			return
+		}
+	}
+
+	trend.Points = append(trend.Points, Point{
+		Commit:      record.Commit,
+		Time:        record.Time,
+		Runs:        1,
+		Duration:    record.Duration,
+		Allocations: record.Allocations,
+		Answer:      record.Answer,
+	})
+}
+
+func (trend *Trend) compare(threshold float64) {
+	for i := 1; i < len(trend.Points); i++ {
+		previous, point := trend.Points[i-1], &trend.Points[i]
+
+		point.DurationChange = relativeChange(float64(previous.Duration), float64(point.Duration))
+		point.AllocationsChange = relativeChange(float64(previous.Allocations), float64(point.Allocations))
+		point.AnswerChanged = previous.Answer != point.Answer
+		point.Regression = point.DurationChange > threshold || point.AllocationsChange > threshold
+	}
+}
+
+func (trend Trend) Latest() Point {
+	return trend.Points[len(trend.Points)-1]
+}
+
+func relativeChange(previous, current float64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	return current/previous - 1
+}
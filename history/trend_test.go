@@ -0,0 +1,104 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func record(commit string, day int, part int, duration time.Duration, allocations uint64, answer int) Record {
+	return Record{Commit: commit, Variant: "input", Day: day, Part: part,
+		Duration: duration, Allocations: allocations, Answer: answer}
+}
+
+func TestTrendsGrouping(t *testing.T) {
+	records := []Record{
+		record("b", 2, 1, 10, 1, 7),
+		record("a", 1, 2, 10, 1, 7),
+		record("a", 1, 1, 30, 5, 7),
+		record("a", 1, 1, 20, 6, 7),
+		record("b", 1, 1, 25, 5, 7),
+		record("c-dirty", 1, 1, 25, 5, 7),
+		record("c-dirty", 1, 1, 25, 5, 7),
+		{Commit: "d", Variant: "input", Day: 1, Part: 1, Error: "failed"},
+	}
+	records[1].Variant = "input_test"
+
+	trends := Trends(records, 0.2)
+
+	var keys []trendKey
+	for _, trend := range trends {
+		keys = append(keys, trendKey{trend.Day, trend.Part, trend.Variant})
+	}
+	expectedKeys := []trendKey{{1, 1, "input"}, {1, 2, "input_test"}, {2, 1, "input"}}
+	if !reflect.DeepEqual(keys, expectedKeys) {
+		t.Fatalf("trends %v, expected %v", keys, expectedKeys)
+	}
+
+	// the runs of a commit are merged, the dirty ones and the failed ones are not
+	var commits []string
+	for _, point := range trends[0].Points {
+		commits = append(commits, point.Commit)
+	}
+	if expected := []string{"a", "b", "c-dirty", "c-dirty"}; !reflect.DeepEqual(commits, expected) {
+		t.Errorf("commits %v, expected %v", commits, expected)
+	}
+
+	merged := trends[0].Points[0]
+	if merged.Runs != 2 || merged.Duration != 20 || merged.Allocations != 5 {
+		t.Errorf("merged point %+v, expected 2 runs, the best time 20 and the fewest allocations 5", merged)
+	}
+}
+
+func TestTrendsRegressions(t *testing.T) {
+	tests := []struct {
+		name          string
+		previous      Record
+		latest        Record
+		regression    bool
+		answerChanged bool
+	}{
+		{"same", record("a", 1, 1, 100, 10, 1), record("b", 1, 1, 100, 10, 1), false, false},
+		{"within the threshold", record("a", 1, 1, 100, 10, 1), record("b", 1, 1, 120, 12, 1), false, false},
+		{"slower", record("a", 1, 1, 100, 10, 1), record("b", 1, 1, 130, 10, 1), true, false},
+		{"more allocations", record("a", 1, 1, 100, 10, 1), record("b", 1, 1, 100, 13, 1), true, false},
+		{"faster", record("a", 1, 1, 100, 10, 1), record("b", 1, 1, 50, 5, 1), false, false},
+		{"no allocations before", record("a", 1, 1, 100, 0, 1), record("b", 1, 1, 100, 10, 1), false, false},
+		{"answer changed", record("a", 1, 1, 100, 10, 1), record("b", 1, 1, 100, 10, 2), false, true},
+	}
+
+	for _, test := range tests {
+		trends := Trends([]Record{test.previous, test.latest}, 0.25)
+		if len(trends) != 1 || len(trends[0].Points) != 2 {
+			t.Fatalf("%s: trends %+v, expected a single one with 2 points", test.name, trends)
+		}
+
+		first, latest := trends[0].Points[0], trends[0].Latest()
+		if first.Regression || first.DurationChange != 0 || first.AnswerChanged {
+			t.Errorf("%s: the first point %+v is compared to nothing", test.name, first)
+		}
+		if latest.Regression != test.regression {
+			t.Errorf("%s: regression %v (%+.2f time, %+.2f allocations), expected %v", test.name,
+				latest.Regression, latest.DurationChange, latest.AllocationsChange, test.regression)
+		}
+		if latest.AnswerChanged != test.answerChanged {
+			t.Errorf("%s: answer changed %v, expected %v", test.name, latest.AnswerChanged, test.answerChanged)
+		}
+	}
+}
+
+func TestTrendsCompareToPreviousCommit(t *testing.T) {
+	records := []Record{
+		record("a", 1, 1, 100, 10, 1),
+		record("b", 1, 1, 200, 10, 1),
+		record("c", 1, 1, 210, 10, 1),
+	}
+
+	points := Trends(records, 0.1)[0].Points
+	if !points[1].Regression || points[1].DurationChange != 1 {
+		t.Errorf("second point %+v, expected a regression of +100%%", points[1])
+	}
+	if points[2].Regression || points[2].DurationChange > 0.051 {
+		t.Errorf("third point %+v, expected +5%% against the second one, not a regression", points[2])
+	}
+}
@@ -0,0 +1,54 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Lines reads the puzzle input line by line, without the line endings
+func Lines(reader io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// CheckGrid reports all the structural problems of a grid at once: an empty grid,
+// rows of different lengths and bytes not in allowed (any byte when allowed is empty).
+// The line numbers start at firstLine, so grids in the middle of the input can be checked.
+func CheckGrid(lines []string, allowed string, firstLine int) []error {
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 || len(lines[0]) == 0 {
+		return []error{fmt.Errorf("line %d: empty grid", firstLine)}
+	}
+
+	var problems []error
+	for i, line := range lines {
+		if len(line) != len(lines[0]) {
+			problems = append(problems, fmt.Errorf("line %d: row has %d columns, the first row has %d",
+				firstLine+i, len(line), len(lines[0])))
+		}
+
+		if allowed == "" {
+			continue
+		}
+		if column := strings.IndexFunc(line, func(char rune) bool { return !strings.ContainsRune(allowed, char) }); column >= 0 {
+			problems = append(problems, fmt.Errorf("line %d, column %d: unexpected %q, expected one of %q",
+				firstLine+i, column+1, line[column], allowed))
+		}
+	}
+
+	return problems
+}
@@ -1,84 +1,132 @@
 package main
 
 import (
-	"aoc2023/day01"
-	"aoc2023/day02"
-	"aoc2023/day03"
-	"aoc2023/day04"
-	"aoc2023/day05"
-	"aoc2023/day06"
-	"aoc2023/day07"
-	"aoc2023/day08"
-	"aoc2023/day09"
-	"aoc2023/day10"
-	"aoc2023/day11"
-	"aoc2023/day12"
-	"aoc2023/day13"
-	"aoc2023/day14"
-	"aoc2023/day15"
-	"aoc2023/day16"
-	"aoc2023/day17"
-	"aoc2023/day18"
-	"aoc2023/day19"
-	"aoc2023/day20"
+	"aoc2023/config"
+	"aoc2023/export"
+	"aoc2023/history"
+	"aoc2023/parallel"
+	"aoc2023/runner"
+	"aoc2023/visual"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"time"
 )
 
+// effective settings of the runner: defaults, config files, environment and flags
+var settings *config.Config
+
 // Run the day based
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintln(os.Stderr, "Please provide a day number")
+	var configErr error
+	settings, configErr = config.Load(config.Settings{
+		Workers:     parallel.Workers,
+		Color:       visual.COLOR_AUTO,
+		InputsDir:   "inputs",
+		Timeout:     config.Duration(10 * time.Second),
+		Format:      "text",
+		SessionFile: config.DefaultSessionFile(),
+		History:     history.DefaultPath(),
+	})
+
+	flag.IntVar(&parallel.Workers, "workers", settings.Workers, "number of workers for parallel solvers")
+	visualize := flag.Bool("visualize", false, "play back the frames emitted by the solver (days 14, 16, 17)")
+	speed := flag.Float64("speed", 10, "visualization speed in frames per second")
+	flag.StringVar(&export.Directory, "render", "", "directory to write PNG and SVG pictures of the results to (days 10, 16, 17, 18)")
+	flag.IntVar(&export.Scale, "scale", export.Scale, "size of a grid cell in the rendered pictures, in pixels")
+	colorMode := flag.String("color", settings.Color, "use terminal colors: auto, always or never (auto honors NO_COLOR)")
+	flag.Usage = usage
+	flag.Parse()
+
+	// the flags override the config files and the environment
+	flagErrs := []error{configErr}
+	flag.Visit(func(f *flag.Flag) {
+		if slices.Contains(config.Names(), f.Name) {
+			flagErrs = append(flagErrs, settings.Set(f.Name, f.Value.String(), "flag -"+f.Name))
+		}
+	})
+	flagErrs = append(flagErrs, settings.Validate())
+	configErr = errors.Join(flagErrs...)
+
+	if !(*speed > 0) {
+		fmt.Fprintf(os.Stderr, "invalid -speed %v: it must be a positive number of frames per second\n", *speed)
+		os.Exit(2)
+	}
+
+	if export.Scale <= 0 {
+		fmt.Fprintf(os.Stderr, "invalid -scale %d: it must be a positive number of pixels\n", export.Scale)
+		os.Exit(2)
+	}
+
+	if err := visual.SetColorMode(*colorMode); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *visualize {
+		visual.Default = &visual.Recorder{}
+	}
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// a broken config does not stop the commands that do not depend on the settings
+	if configErr != nil && slices.Contains(SETTINGS_FREE_COMMANDS, flag.Arg(0)) {
+		fmt.Fprintln(os.Stderr, "warning:", configErr)
+	} else if configErr != nil {
+		fmt.Fprintln(os.Stderr, configErr)
+		os.Exit(2)
+	}
+
+	if command, ok := findCommand(flag.Arg(0)); ok {
+		if err := command.Run(flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	dayNumber, err := strconv.Atoi(os.Args[1])
+	dayNumber, err := strconv.Atoi(flag.Arg(0))
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Please provide a valid day number")
 		return
 	}
 
-	switch dayNumber {
-	case 1: // day01
-		day01.Run()
-	case 2: // day02
-		day02.Run()
-	case 3: // day03
-		day03.Run()
-	case 4: // day04
-		day04.Run()
-	case 5: // day05
-		day05.Run()
-	case 6: // day06
-		day06.Run()
-	case 7: // day07
-		day07.Run()
-	case 8: // day08
-		day08.Run()
-	case 9: // day09
-		day09.Run()
-	case 10: // day10
-		day10.Run()
-	case 11: // day11
-		day11.Run()
-	case 12: // day12
-		day12.Run()
-	case 13: // day13
-		day13.Run()
-	case 14: // day14
-		day14.Run()
-	case 15: // day15
-		day15.Run()
-	case 16: // day16
-		day16.Run()
-	case 17: // day17
-		day17.Run()
-	case 18: // day18
-		day18.Run()
-	case 19: // day19
-		day19.Run()
-	case 20: // day20
-		day20.Run()
+	day, ok := runner.Find(dayNumber)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Please provide a valid day number")
+		return
+	}
+
+	day.Run()
+
+	if err := export.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *visualize {
+		player := visual.Player{
+			Frames: visual.Default.Frames,
+			Delay:  max(time.Duration(float64(time.Second) / *speed), time.Nanosecond),
+			Input:  os.Stdin,
+			Output: os.Stdout,
+		}
+		player.Play()
+	}
+}
+
+func usage() {
+	output := flag.CommandLine.Output()
+	fmt.Fprintf(output, "usage: %s [flags] <day>\n       %s [flags] <command> [arguments]\n\ncommands:\n", os.Args[0], os.Args[0])
+	for _, command := range commands {
+		fmt.Fprintf(output, "  %s\n", command.Usage)
 	}
+	fmt.Fprintln(output, "\nflags:")
+	flag.PrintDefaults()
 }
@@ -0,0 +1,97 @@
+package parallel
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Number of workers used by Map, defaults to the number of usable CPUs
+var Workers = runtime.GOMAXPROCS(0)
+
+var (
+	// guards Workers while WithWorkers overrides it
+	workersLock sync.RWMutex
+	// keeps the overrides from restoring each other's value
+	overrideLock sync.Mutex
+)
+
+// Map applies fn to every item on a pool of Workers goroutines and returns
+// the results in the order of the items. fn must not share mutable state
+// between calls, every call gets its own state. A panic in fn is re-raised
+// in the calling goroutine, so the caller can recover from it.
+func Map[T, R any](items []T, fn func(T) R) []R {
+	results := make([]R, len(items))
+	indices := make(chan int)
+	workersLock.RLock()
+	workers := Workers
+	workersLock.RUnlock()
+
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	var wg sync.WaitGroup
+	var panicOnce sync.Once
+	var panicValue any
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panicOnce.Do(func() { panicValue = r })
+					// keep draining the items, so the feeding loop does not block
+					for range indices {
+					}
+				}
+			}()
+
+			for i := range indices {
+				results[i] = fn(items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	if panicValue != nil {
+		panic(panicValue)
+	}
+	return results
+}
+
+// Sum applies fn to every item on the worker pool and sums the results
+func Sum[T any](items []T, fn func(T) int) int {
+	sum := 0
+	for _, value := range Map(items, fn) {
+		sum += value
+	}
+	return sum
+}
+
+// WithWorkers runs fn with Workers set to the given number and restores it afterwards,
+// e.g. to time a sequential run. One override runs at a time.
+func WithWorkers(workers int, fn func()) {
+	overrideLock.Lock()
+	defer overrideLock.Unlock()
+
+	workersLock.Lock()
+	previous := Workers
+	Workers = workers
+	workersLock.Unlock()
+
+	defer func() {
+		workersLock.Lock()
+		Workers = previous
+		workersLock.Unlock()
+	}()
+	fn()
+}
@@ -0,0 +1,89 @@
+package parallel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func square(value int) int {
+	return value * value
+}
+
+func TestMapMatchesSequentialRun(t *testing.T) {
+	items := make([]int, 1000)
+	expected := make([]int, len(items))
+	expectedSum := 0
+	for i := range items {
+		items[i] = i - 500
+		expected[i] = square(items[i])
+		expectedSum += expected[i]
+	}
+
+	for _, workers := range []int{-1, 0, 1, 2, 3, 8, 2000} {
+		WithWorkers(workers, func() {
+			if results := Map(items, square); !reflect.DeepEqual(results, expected) {
+				t.Errorf("%d workers: the results differ from a sequential run", workers)
+			}
+			if sum := Sum(items, square); sum != expectedSum {
+				t.Errorf("%d workers: sum %d, expected %d", workers, sum, expectedSum)
+			}
+		})
+	}
+}
+
+func TestMapEmptyInput(t *testing.T) {
+	if results := Map([]int{}, square); len(results) != 0 {
+		t.Errorf("results %v of no items, expected none", results)
+	}
+	if results := Map(nil, square); len(results) != 0 {
+		t.Errorf("results %v of nil items, expected none", results)
+	}
+	if sum := Sum(nil, square); sum != 0 {
+		t.Errorf("sum %d of no items, expected 0", sum)
+	}
+}
+
+func TestMapPanicReachesCaller(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	for _, workers := range []int{1, 4} {
+		WithWorkers(workers, func() {
+			defer func() {
+				if r := recover(); r != "item 42" {
+					t.Errorf("%d workers: recovered %v, expected the panic of item 42", workers, r)
+				}
+			}()
+
+			Map(items, func(item int) int {
+				if item == 42 {
+					panic("item 42")
+				}
+				return item
+			})
+		})
+	}
+}
+
+func TestWithWorkersRestores(t *testing.T) {
+	workers := Workers
+
+	WithWorkers(1, func() {
+		if Workers != 1 {
+			t.Errorf("workers %d within the override, expected 1", Workers)
+		}
+	})
+	if Workers != workers {
+		t.Errorf("workers %d after the override, expected %d", Workers, workers)
+	}
+
+	func() {
+		defer func() { recover() }()
+		WithWorkers(3, func() { panic("fail") })
+	}()
+	if Workers != workers {
+		t.Errorf("workers %d after a panicking override, expected %d", Workers, workers)
+	}
+}
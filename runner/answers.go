@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const ANSWERS_FILE_NAME = "answers.json"
+
+// Answers are the expected answers of a day, keyed by the input variant and the part,
+// e.g. {"input": {"1": 142, "2": 281}}
+type Answers map[string]map[int]int
+
+func (day Day) AnswersPath() string {
+	return filepath.Join(day.Dir(), ANSWERS_FILE_NAME)
+}
+
+// Answers reads the recorded answers of the day, a missing file means no answers
+func (day Day) Answers() (Answers, error) {
+	return ReadAnswers(day.AnswersPath())
+}
+
+func ReadAnswers(path string) (Answers, error) {
+	content, err := os.ReadFile(path)
+	return decodeAnswers(content, err, path)
+}
+
+// decodeAnswers decodes the content of an answers file, a missing file means no answers
+func decodeAnswers(content []byte, readErr error, path string) (Answers, error) {
+	answers := make(Answers)
+
+	if errors.Is(readErr, fs.ErrNotExist) {
+		return answers, nil
+	}
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	if err := json.Unmarshal(content, &answers); err != nil {
+		return nil, fmt.Errorf("invalid answers file %s: %w", path, err)
+	}
+	return answers, nil
+}
+
+func (answers Answers) Expected(variant string, part int) (int, bool) {
+	answer, ok := answers[variant][part]
+	return answer, ok
+}
+
+// Verdict compares the result with the recorded answer
+func (answers Answers) Verdict(variant string, result Result) string {
+	expected, ok := answers.Expected(variant, result.Part)
+	switch {
+	case errors.Is(result.Err, ErrPanic):
+		return "PANIC"
+	case errors.Is(result.Err, ErrTimeout):
+		return "TIMEOUT"
+	case result.Err != nil:
+		return "FAILED"
+	case !ok:
+		return "UNKNOWN"
+	case expected == result.Answer:
+		return "OK"
+	default:
+		return fmt.Sprintf("WRONG (expected %d)", expected)
+	}
+}
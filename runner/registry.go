@@ -0,0 +1,160 @@
+package runner
+
+import (
+	"aoc2023/day01"
+	"aoc2023/day02"
+	"aoc2023/day03"
+	"aoc2023/day04"
+	"aoc2023/day05"
+	"aoc2023/day06"
+	"aoc2023/day07"
+	"aoc2023/day08"
+	"aoc2023/day09"
+	"aoc2023/day10"
+	"aoc2023/day11"
+	"aoc2023/day12"
+	"aoc2023/day13"
+	"aoc2023/day14"
+	"aoc2023/day15"
+	"aoc2023/day16"
+	"aoc2023/day17"
+	"aoc2023/day18"
+	"aoc2023/day19"
+	"aoc2023/day20"
+	"aoc2023/trace"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Solver computes the answer of a single part from the lines of the input
+type Solver func(lines []string) (int, error)
+
+// Reformatter parses the input of a day and writes it back in the canonical form
+type Reformatter func(reader io.Reader, writer io.Writer) error
+
+type Day struct {
+	Number    int
+	Title     string
+	InputPath string
+	Run       func()
+	Parts     []Solver
+	Reformat  Reformatter
+
+	// the examples from the puzzle description, with their answers in samples/answers.json
+	Samples fs.FS
+}
+
+var Days = []Day{
+	{1, day01.TITLE, day01.INPUT_FILE_PATH, day01.Run, []Solver{day01.Part1, day01.Part2}, reformat(day01.Parse, day01.Format), day01.Samples},
+	{2, day02.TITLE, day02.INPUT_FILE_PATH, day02.Run, []Solver{day02.Part1, day02.Part2}, reformat(day02.Parse, day02.Format), day02.Samples},
+	{3, day03.TITLE, day03.INPUT_FILE_PATH, day03.Run, []Solver{day03.Part1, day03.Part2}, reformat(day03.Parse, day03.Format), day03.Samples},
+	{4, day04.TITLE, day04.INPUT_FILE_PATH, day04.Run, []Solver{day04.Part1, day04.Part2}, reformat(day04.Parse, day04.Format), day04.Samples},
+	{5, day05.TITLE, day05.INPUT_FILE_PATH, day05.Run, []Solver{day05.Part1, day05.Part2}, reformat(day05.Parse, day05.Format), day05.Samples},
+	{6, day06.TITLE, day06.INPUT_FILE_PATH, day06.Run, []Solver{day06.Part1, day06.Part2}, reformat(day06.Parse, day06.Format), day06.Samples},
+	{7, day07.TITLE, day07.INPUT_FILE_PATH, day07.Run, []Solver{day07.Part1, day07.Part2}, reformat(day07.Parse, day07.Format), day07.Samples},
+	{8, day08.TITLE, day08.INPUT_FILE_PATH, day08.Run, []Solver{day08.Part1, day08.Part2}, reformat(day08.Parse, day08.Format), day08.Samples},
+	{9, day09.TITLE, day09.INPUT_FILE_PATH, day09.Run, []Solver{day09.Part1, day09.Part2}, reformat(day09.Parse, day09.Format), day09.Samples},
+	{10, day10.TITLE, day10.INPUT_FILE_PATH, day10.Run, []Solver{day10.Part1, day10.Part2}, reformat(day10.Parse, day10.Format), day10.Samples},
+	{11, day11.TITLE, day11.INPUT_FILE_PATH, day11.Run, []Solver{day11.Part1, day11.Part2}, reformat(day11.Parse, day11.Format), day11.Samples},
+	{12, day12.TITLE, day12.INPUT_FILE_PATH, day12.Run, []Solver{day12.Part1, day12.Part2}, reformat(day12.Parse, day12.Format), day12.Samples},
+	{13, day13.TITLE, day13.INPUT_FILE_PATH, day13.Run, []Solver{day13.Part1, day13.Part2}, reformat(day13.Parse, day13.Format), day13.Samples},
+	{14, day14.TITLE, day14.INPUT_FILE_PATH, day14.Run, []Solver{day14.Part1, day14.Part2}, reformat(day14.Parse, day14.Format), day14.Samples},
+	{15, day15.TITLE, day15.INPUT_FILE_PATH, day15.Run, []Solver{day15.Part1, day15.Part2}, reformat(day15.Parse, day15.Format), day15.Samples},
+	{16, day16.TITLE, day16.INPUT_FILE_PATH, day16.Run, []Solver{day16.Part1, day16.Part2}, reformat(day16.Parse, day16.Format), day16.Samples},
+	{17, day17.TITLE, day17.INPUT_FILE_PATH, day17.Run, []Solver{day17.Part1, day17.Part2}, reformat(day17.Parse, day17.Format), day17.Samples},
+	{18, day18.TITLE, day18.INPUT_FILE_PATH, day18.Run, []Solver{day18.Part1, day18.Part2}, reformat(day18.Parse, day18.Format), day18.Samples},
+	{19, day19.TITLE, day19.INPUT_FILE_PATH, day19.Run, []Solver{day19.Part1, day19.Part2}, reformatDay19, day19.Samples},
+	{20, day20.TITLE, day20.INPUT_FILE_PATH, day20.Run, []Solver{day20.Part1, day20.Part2}, reformat(day20.Parse, day20.Format), day20.Samples},
+}
+
+// Explainer traces how the solver handles a single item of the input, e.g. the line of a hand
+type Explainer func(lines []string, item int, tracer *trace.Tracer) error
+
+// Explainers of the days that support tracing
+var Explainers = map[int]Explainer{
+	4:  day04.Explain,
+	7:  day07.Explain,
+	19: day19.Explain,
+}
+
+// Validator reports all the structural problems of the input at once
+type Validator func(lines []string) []error
+
+// Validators of the days with structural checks beyond parsing the input
+var Validators = map[int]Validator{
+	1:  day01.Validate,
+	2:  day02.Validate,
+	3:  day03.Validate,
+	5:  day05.Validate,
+	10: day10.Validate,
+	11: day11.Validate,
+	13: day13.Validate,
+	14: day14.Validate,
+	16: day16.Validate,
+	17: day17.Validate,
+	19: day19.Validate,
+	20: day20.Validate,
+}
+
+func reformat[M any](parse func(io.Reader) (M, error), format func(io.Writer, M) error) Reformatter {
+	return func(reader io.Reader, writer io.Writer) error {
+		model, err := parse(reader)
+		if err != nil {
+			return err
+		}
+		return format(writer, model)
+	}
+}
+
+// the workflows and the parts of day 19 are separate models
+func reformatDay19(reader io.Reader, writer io.Writer) error {
+	system, parts, err := day19.Parse(reader)
+	if err != nil {
+		return err
+	}
+	return day19.Format(writer, system, parts)
+}
+
+func Find(number int) (Day, bool) {
+	for _, day := range Days {
+		if day.Number == number {
+			return day, true
+		}
+	}
+	return Day{}, false
+}
+
+// Variants lists the input files of the day, named after the file
+// without the extension, e.g. day05/input_test.txt is "input_test"
+func (day Day) Variants() []string {
+	paths, _ := filepath.Glob(filepath.Join(day.Dir(), "input*.txt"))
+	variants := []string{}
+
+	for _, path := range paths {
+		variants = append(variants, strings.TrimSuffix(filepath.Base(path), ".txt"))
+	}
+
+	sort.Strings(variants)
+	return variants
+}
+
+// DefaultVariant is the variant of the day's INPUT_FILE_PATH
+func (day Day) DefaultVariant() string {
+	return strings.TrimSuffix(filepath.Base(day.InputPath), ".txt")
+}
+
+func (day Day) Dir() string {
+	return filepath.Dir(day.InputPath)
+}
+
+func (day Day) VariantPath(variant string) string {
+	return filepath.Join(day.Dir(), variant+".txt")
+}
+
+func (day Day) String() string {
+	return fmt.Sprintf("Day %02d: %s", day.Number, day.Title)
+}
@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"aoc2023/input"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+const SAMPLES_DIR = "samples"
+
+// SampleNames lists the embedded examples of the day, named after the file
+// without the extension, e.g. samples/example_part2.txt is "example_part2"
+func (day Day) SampleNames() []string {
+	paths, _ := fs.Glob(day.Samples, path.Join(SAMPLES_DIR, "*.txt"))
+	names := []string{}
+
+	for _, samplePath := range paths {
+		names = append(names, strings.TrimSuffix(path.Base(samplePath), ".txt"))
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func (day Day) ReadSample(name string) ([]string, error) {
+	fd, err := day.Samples.Open(path.Join(SAMPLES_DIR, name+".txt"))
+	if err != nil {
+		return nil, fmt.Errorf("%s has no sample %s, available: %s", day, name, strings.Join(day.SampleNames(), ", "))
+	}
+	defer fd.Close()
+
+	return input.Lines(fd)
+}
+
+// SampleAnswers are the answers from the puzzle description, keyed by the sample name
+func (day Day) SampleAnswers() (Answers, error) {
+	answersPath := path.Join(SAMPLES_DIR, ANSWERS_FILE_NAME)
+	content, err := fs.ReadFile(day.Samples, answersPath)
+	return decodeAnswers(content, err, day.Dir()+"/"+answersPath)
+}
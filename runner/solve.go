@@ -0,0 +1,207 @@
+package runner
+
+import (
+	"aoc2023/input"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+type Result struct {
+	Day      int
+	Part     int
+	Answer   int
+	Duration time.Duration
+	Err      error
+
+	// heap allocations made while solving, other goroutines are counted in as well
+	Allocations    uint64
+	AllocatedBytes uint64
+}
+
+var ErrPanic = errors.New("panic")
+var ErrTimeout = errors.New("time budget exceeded")
+
+// kinds of the errors that are kept when a result is encoded as JSON
+var ERROR_KINDS = map[string]error{
+	"panic":   ErrPanic,
+	"timeout": ErrTimeout,
+}
+
+// os.Stdout is process wide, so only a single solver can have its output captured
+var stdoutMutex sync.Mutex
+
+func ReadInput(path string) ([]string, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	return input.Lines(fd)
+}
+
+// Solve runs a single part of the day, a panic of the solver is reported as an error.
+// When output is not nil, everything the solver prints to stdout is written to it.
+func (day Day) Solve(part int, lines []string, output io.Writer) Result {
+	result := Result{Day: day.Number, Part: part}
+
+	if part < 1 || part > len(day.Parts) {
+		result.Err = fmt.Errorf("day %d has no part %d", day.Number, part)
+		return result
+	}
+
+	solve := func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result.Err = fmt.Errorf("%w: %v", ErrPanic, r)
+			}
+		}()
+
+		var memStatsBefore, memStatsAfter runtime.MemStats
+		runtime.ReadMemStats(&memStatsBefore)
+
+		startTime := time.Now()
+		result.Answer, result.Err = day.Parts[part-1](lines)
+		result.Duration = time.Since(startTime)
+
+		runtime.ReadMemStats(&memStatsAfter)
+		result.Allocations = memStatsAfter.Mallocs - memStatsBefore.Mallocs
+		result.AllocatedBytes = memStatsAfter.TotalAlloc - memStatsBefore.TotalAlloc
+	}
+
+	if output == nil {
+		solve()
+	} else {
+		captureStdout(output, solve)
+	}
+
+	return result
+}
+
+// SolveWithBudget runs a single part like Solve, but gives up waiting for the
+// answer when it takes longer than the budget. Go cannot stop the solver,
+// so it keeps running in the background until it finishes.
+func (day Day) SolveWithBudget(part int, lines []string, budget time.Duration) Result {
+	solved := make(chan Result, 1)
+	go func() {
+		solved <- day.Solve(part, lines, nil)
+	}()
+
+	select {
+	case result := <-solved:
+		return result
+	case <-time.After(budget):
+		return Result{Day: day.Number, Part: part, Duration: budget, Err: ErrTimeout}
+	}
+}
+
+func captureStdout(output io.Writer, fn func()) {
+	_, restore := RedirectStdout(output)
+	defer restore()
+
+	fn()
+}
+
+// RedirectStdout sends everything printed to stdout to output until restore is called and returns
+// the original stdout to print the results to. It holds the same lock as Solve capturing a solver,
+// so only Solve without an output can be called until the restore.
+func RedirectStdout(output io.Writer) (stdout *os.File, restore func()) {
+	stdoutMutex.Lock()
+	originalStdout := os.Stdout
+
+	if file, ok := output.(*os.File); ok {
+		os.Stdout = file
+		return originalStdout, func() {
+			os.Stdout = originalStdout
+			stdoutMutex.Unlock()
+		}
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return originalStdout, stdoutMutex.Unlock
+	}
+
+	copied := make(chan bool)
+	go func() {
+		io.Copy(output, reader)
+		close(copied)
+	}()
+
+	os.Stdout = writer
+	return originalStdout, func() {
+		os.Stdout = originalStdout
+		writer.Close()
+		<-copied
+		reader.Close()
+		stdoutMutex.Unlock()
+	}
+}
+
+type resultJSON struct {
+	Day            int           `json:"day"`
+	Part           int           `json:"part"`
+	Answer         int           `json:"answer"`
+	Duration       time.Duration `json:"duration"`
+	Error          string        `json:"error,omitempty"`
+	ErrorKind      string        `json:"kind,omitempty"`
+	Allocations    uint64        `json:"allocations"`
+	AllocatedBytes uint64        `json:"allocated_bytes"`
+}
+
+func (result Result) MarshalJSON() ([]byte, error) {
+	encoded := resultJSON{
+		result.Day, result.Part, result.Answer, result.Duration, "", "", result.Allocations, result.AllocatedBytes,
+	}
+	if result.Err != nil {
+		encoded.Error = result.Err.Error()
+	}
+	for kind, kindErr := range ERROR_KINDS {
+		if errors.Is(result.Err, kindErr) {
+			encoded.ErrorKind = kind
+		}
+	}
+	return json.Marshal(encoded)
+}
+
+func (result *Result) UnmarshalJSON(data []byte) error {
+	var decoded resultJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*result = Result{
+		decoded.Day, decoded.Part, decoded.Answer, decoded.Duration, nil, decoded.Allocations, decoded.AllocatedBytes,
+	}
+	if decoded.Error != "" {
+		result.Err = decodedError{decoded.Error, ERROR_KINDS[decoded.ErrorKind]}
+	}
+	return nil
+}
+
+// decodedError keeps the message of a decoded error, errors.Is still matches its kind
+type decodedError struct {
+	message string
+	kind    error
+}
+
+func (err decodedError) Error() string {
+	return err.message
+}
+
+func (err decodedError) Unwrap() error {
+	return err.kind
+}
+
+func (result Result) String() string {
+	if result.Err != nil {
+		return fmt.Sprintf("error: %v", result.Err)
+	}
+	return fmt.Sprintf("%d (%v)", result.Answer, result.Duration)
+}
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRedirectStdout(t *testing.T) {
+	originalStdout := os.Stdout
+	output := &strings.Builder{}
+
+	stdout, restore := RedirectStdout(output)
+	fmt.Println("diagnostics")
+	restore()
+
+	if stdout != originalStdout || os.Stdout != originalStdout {
+		t.Errorf("the original stdout is not returned and restored")
+	}
+	if output.String() != "diagnostics\n" {
+		t.Errorf("redirected %q, expected the diagnostics", output.String())
+	}
+}
+
+func TestSolveCapturesOutput(t *testing.T) {
+	printing := func(lines []string) (int, error) {
+		fmt.Println("solving", len(lines))
+		return len(lines), nil
+	}
+	day := Day{Number: 1, Parts: []Solver{printing}}
+
+	output := &strings.Builder{}
+	result := day.Solve(1, []string{"a", "b"}, output)
+	if result.Err != nil || result.Answer != 2 {
+		t.Errorf("answer %d (%v), expected 2", result.Answer, result.Err)
+	}
+	if output.String() != "solving 2\n" {
+		t.Errorf("captured %q, expected the solver output", output.String())
+	}
+
+	// a command redirecting its stdout can still solve without an output
+	_, restore := RedirectStdout(&strings.Builder{})
+	defer restore()
+	if result := day.Solve(1, nil, nil); result.Err != nil {
+		t.Error(result.Err)
+	}
+}
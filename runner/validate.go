@@ -0,0 +1,35 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Validate runs the structural checks of the day and, when they pass, parses
+// the input to catch the rest, a panicking parser is reported as a problem too
+func (day Day) Validate(lines []string) []error {
+	if validate, ok := Validators[day.Number]; ok {
+		if problems := validate(lines); len(problems) > 0 {
+			return problems
+		}
+	}
+
+	if err := day.parse(lines); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+func (day Day) parse(lines []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("parser panics: %v", r)
+		}
+	}()
+
+	if err := day.Reformat(strings.NewReader(strings.Join(lines, "\n")), io.Discard); err != nil {
+		return fmt.Errorf("cannot parse: %w", err)
+	}
+	return nil
+}
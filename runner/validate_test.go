@@ -0,0 +1,18 @@
+package runner
+
+import "testing"
+
+func TestSamplesAreValid(t *testing.T) {
+	for _, day := range Days {
+		for _, name := range day.SampleNames() {
+			lines, err := day.ReadSample(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, problem := range day.Validate(lines) {
+				t.Errorf("%s, sample %s: %v", day, name, problem)
+			}
+		}
+	}
+}
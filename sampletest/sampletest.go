@@ -0,0 +1,113 @@
+package sampletest
+
+import (
+	"aoc2023/input"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Sample is an embedded example of a day with the answers from the puzzle description
+type Sample struct {
+	Name    string
+	Input   string
+	Answers map[int]int
+}
+
+// Load reads the examples embedded in the samples directory of a day, see runner.Day.Samples
+func Load(t testing.TB, samples fs.FS) []Sample {
+	t.Helper()
+
+	content, err := fs.ReadFile(samples, "samples/answers.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := map[string]map[int]int{}
+	if err := json.Unmarshal(content, &answers); err != nil {
+		t.Fatalf("invalid samples/answers.json: %v", err)
+	}
+
+	paths, err := fs.Glob(samples, "samples/*.txt")
+	if err != nil || len(paths) == 0 {
+		t.Fatal("no samples embedded")
+	}
+
+	var result []Sample
+	for _, samplePath := range paths {
+		content, err := fs.ReadFile(samples, samplePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name := strings.TrimSuffix(path.Base(samplePath), ".txt")
+		result = append(result, Sample{name, string(content), answers[name]})
+	}
+	return result
+}
+
+// RoundTrip parses every sample, formats it and parses it again. The model parsed back has to be
+// equal to the original one and the parts have to give the sample answers from the formatted input.
+func RoundTrip[M any](t *testing.T, samples fs.FS, parse func(io.Reader) (M, error), format func(io.Writer, M) error,
+	parts ...func([]string) (int, error)) {
+	for _, sample := range Load(t, samples) {
+		t.Run(sample.Name, func(t *testing.T) {
+			formatted := CheckRoundTrip(t, sample.Input, parse, format)
+			CheckAnswers(t, sample, formatted, parts...)
+		})
+	}
+}
+
+// CheckRoundTrip parses the text, formats it and parses it again, the model parsed back
+// has to be equal to the original one. It returns the formatted text.
+func CheckRoundTrip[M any](t *testing.T, text string, parse func(io.Reader) (M, error), format func(io.Writer, M) error) string {
+	t.Helper()
+
+	parsed, err := parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("cannot parse the input: %v", err)
+	}
+
+	formatted := &strings.Builder{}
+	if err := format(formatted, parsed); err != nil {
+		t.Fatalf("cannot format the input: %v", err)
+	}
+
+	reparsed, err := parse(strings.NewReader(formatted.String()))
+	if err != nil {
+		t.Fatalf("cannot parse the formatted input: %v\n%s", err, formatted)
+	}
+	if !reflect.DeepEqual(parsed, reparsed) {
+		t.Errorf("the formatted input parses to a different model:\n%s", formatted)
+	}
+	return formatted.String()
+}
+
+// CheckAnswers solves the parts the sample has an answer of from the given input,
+// a part without an answer is not run, it may not even finish on the sample
+func CheckAnswers(t *testing.T, sample Sample, text string, parts ...func([]string) (int, error)) {
+	t.Helper()
+
+	lines, err := input.Lines(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, part := range parts {
+		expected, ok := sample.Answers[i+1]
+		if !ok {
+			continue
+		}
+
+		answer, err := part(lines)
+		if err != nil {
+			t.Errorf("part %d: %v", i+1, err)
+		} else if answer != expected {
+			t.Errorf("part %d: answer %d, expected %d", i+1, answer, expected)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"aoc2023/input"
+	"aoc2023/runner"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_MAX_BODY_SIZE  = 1 << 20
+	DEFAULT_TIMEOUT        = 10 * time.Second
+	DEFAULT_MAX_CONCURRENT = 4
+)
+
+// Server exposes the solvers over HTTP:
+//
+//	GET  /days                 lists the days and their parts
+//	POST /days/{n}/parts/{p}   solves the part with the request body as the input
+type Server struct {
+	Days          []runner.Day
+	MaxBodySize   int64
+	Timeout       time.Duration
+	MaxConcurrent int
+
+	// a solver holds a slot until it finishes, even after its request timed out
+	slots     chan bool
+	slotsOnce sync.Once
+}
+
+type DayInfo struct {
+	Day   int    `json:"day"`
+	Title string `json:"title"`
+	Parts int    `json:"parts"`
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+func NewServer(days []runner.Day) *Server {
+	return &Server{
+		Days:          days,
+		MaxBodySize:   DEFAULT_MAX_BODY_SIZE,
+		Timeout:       DEFAULT_TIMEOUT,
+		MaxConcurrent: DEFAULT_MAX_CONCURRENT,
+	}
+}
+
+func (server *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	path := strings.Split(strings.Trim(request.URL.Path, "/"), "/")
+
+	switch {
+	case len(path) == 1 && path[0] == "days":
+		if request.Method != http.MethodGet {
+			methodNotAllowed(writer, http.MethodGet)
+			return
+		}
+		server.listDays(writer)
+	case len(path) == 4 && path[0] == "days" && path[2] == "parts":
+		if request.Method != http.MethodPost {
+			methodNotAllowed(writer, http.MethodPost)
+			return
+		}
+		server.solve(writer, request, path[1], path[3])
+	default:
+		writeError(writer, http.StatusNotFound, fmt.Errorf("no such endpoint: %s", request.URL.Path))
+	}
+}
+
+func (server *Server) listDays(writer http.ResponseWriter) {
+	days := []DayInfo{}
+	for _, day := range server.Days {
+		days = append(days, DayInfo{day.Number, day.Title, len(day.Parts)})
+	}
+	writeJSON(writer, http.StatusOK, days)
+}
+
+func (server *Server) solve(writer http.ResponseWriter, request *http.Request, dayArg, partArg string) {
+	day, ok := server.findDay(dayArg)
+	if !ok {
+		writeError(writer, http.StatusNotFound, fmt.Errorf("no such day: %s", dayArg))
+		return
+	}
+
+	part, err := strconv.Atoi(partArg)
+	if err != nil || part < 1 || part > len(day.Parts) {
+		writeError(writer, http.StatusNotFound, fmt.Errorf("day %d has no part %s", day.Number, partArg))
+		return
+	}
+
+	lines, err := input.Lines(http.MaxBytesReader(writer, request.Body, server.MaxBodySize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(writer, http.StatusRequestEntityTooLarge, fmt.Errorf("input is larger than %d bytes", tooLarge.Limit))
+		return
+	}
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, err)
+		return
+	}
+
+	if !server.acquire() {
+		writeError(writer, http.StatusServiceUnavailable, errors.New("too many solvers running, try again later"))
+		return
+	}
+
+	solved := make(chan runner.Result, 1)
+	go func() {
+		defer server.release()
+		solved <- day.Solve(part, lines, nil)
+	}()
+
+	timeout := time.NewTimer(server.Timeout)
+	defer timeout.Stop()
+
+	select {
+	case result := <-solved:
+		status := http.StatusOK
+		if result.Err != nil {
+			status = http.StatusUnprocessableEntity
+		}
+		writeJSON(writer, status, result)
+	case <-timeout.C:
+		result := runner.Result{Day: day.Number, Part: part, Duration: server.Timeout, Err: runner.ErrTimeout}
+		writeJSON(writer, http.StatusGatewayTimeout, result)
+	case <-request.Context().Done():
+		// the client is gone, there is no one to answer
+	}
+}
+
+func (server *Server) findDay(arg string) (runner.Day, bool) {
+	number, err := strconv.Atoi(arg)
+	if err != nil {
+		return runner.Day{}, false
+	}
+
+	for _, day := range server.Days {
+		if day.Number == number {
+			return day, true
+		}
+	}
+	return runner.Day{}, false
+}
+
+// acquire takes a solver slot without waiting, the slots are created
+// on first use so a Server can also be built as a struct literal
+func (server *Server) acquire() bool {
+	server.slotsOnce.Do(func() {
+		server.slots = make(chan bool, max(server.MaxConcurrent, 1))
+	})
+
+	select {
+	case server.slots <- true:
+		return true
+	default:
+		return false
+	}
+}
+
+func (server *Server) release() {
+	<-server.slots
+}
+
+func methodNotAllowed(writer http.ResponseWriter, allowed string) {
+	writer.Header().Set("Allow", allowed)
+	writeError(writer, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed, use %s", allowed))
+}
+
+func writeError(writer http.ResponseWriter, status int, err error) {
+	writeJSON(writer, status, errorJSON{err.Error()})
+}
+
+func writeJSON(writer http.ResponseWriter, status int, value any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(value)
+}
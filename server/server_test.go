@@ -0,0 +1,91 @@
+package server
+
+import (
+	"aoc2023/runner"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func countLines(lines []string) (int, error) {
+	return len(lines), nil
+}
+
+func newTestServer(t *testing.T, api *Server) *httptest.Server {
+	testServer := httptest.NewServer(api)
+	t.Cleanup(testServer.Close)
+	return testServer
+}
+
+func post(t *testing.T, testServer *httptest.Server, path string, body string) (*http.Response, map[string]any) {
+	response, err := http.Post(testServer.URL+path, "text/plain", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	decoded := map[string]any{}
+	if err := json.NewDecoder(response.Body).Decode(&decoded); err != nil {
+		t.Fatalf("POST %s: invalid JSON response: %v", path, err)
+	}
+	return response, decoded
+}
+
+func TestSolve(t *testing.T) {
+	api := NewServer([]runner.Day{{Number: 1, Parts: []runner.Solver{countLines, countLines}}})
+	testServer := newTestServer(t, api)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/days/1/parts/2", http.StatusOK},
+		{"/days/2/parts/1", http.StatusNotFound},
+		{"/days/x/parts/1", http.StatusNotFound},
+		{"/days/1/parts/3", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		response, decoded := post(t, testServer, test.path, "a\nb\nc\n")
+		if response.StatusCode != test.status {
+			t.Errorf("POST %s: status %d, expected %d", test.path, response.StatusCode, test.status)
+		}
+		if test.status == http.StatusOK && decoded["answer"] != 3.0 {
+			t.Errorf("POST %s: answer %v, expected 3", test.path, decoded["answer"])
+		}
+		if test.status != http.StatusOK && decoded["error"] == nil {
+			t.Errorf("POST %s: no error in %v", test.path, decoded)
+		}
+	}
+}
+
+func TestSolveTimeoutAndBusy(t *testing.T) {
+	unblock := make(chan bool)
+	blocking := func(lines []string) (int, error) {
+		<-unblock
+		return 0, nil
+	}
+
+	api := NewServer([]runner.Day{{Number: 1, Parts: []runner.Solver{blocking, countLines}}})
+	api.Timeout = 10 * time.Millisecond
+	api.MaxConcurrent = 1
+	testServer := newTestServer(t, api)
+	defer close(unblock)
+
+	response, decoded := post(t, testServer, "/days/1/parts/1", "")
+	if response.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("status %d of a solver over the budget, expected %d", response.StatusCode, http.StatusGatewayTimeout)
+	}
+	if decoded["kind"] != "timeout" {
+		t.Errorf("error kind %v of a solver over the budget, expected timeout", decoded["kind"])
+	}
+
+	// the timed out solver still holds the only slot
+	response, _ = post(t, testServer, "/days/1/parts/2", "")
+	if response.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status %d with all the slots taken, expected %d", response.StatusCode, http.StatusServiceUnavailable)
+	}
+}
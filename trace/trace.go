@@ -0,0 +1,58 @@
+package trace
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type Fields map[string]any
+
+// Event is a single step of a solver, e.g. {"workflow", {"name": "px", "next": "qkq"}}
+type Event struct {
+	Kind   string `json:"kind"`
+	Fields Fields `json:"fields"`
+}
+
+// Tracer collects events emitted by the solvers, a nil tracer ignores them
+type Tracer struct {
+	Events []Event
+}
+
+func (tracer *Tracer) Enabled() bool {
+	return tracer != nil
+}
+
+func (tracer *Tracer) Emit(kind string, fields Fields) {
+	if tracer == nil {
+		return
+	}
+	tracer.Events = append(tracer.Events, Event{kind, fields})
+}
+
+// String prints the event on a single line, the fields sorted by name
+func (event Event) String() string {
+	keys := make([]string, 0, len(event.Fields))
+	for key := range event.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		fields[i] = fmt.Sprintf("%s=%v", key, event.Fields[key])
+	}
+
+	return fmt.Sprintf("%-10s %s", event.Kind, strings.Join(fields, " "))
+}
+
+// Write prints the events as a numbered list of steps
+func Write(writer io.Writer, events []Event) error {
+	for i, event := range events {
+		if _, err := fmt.Fprintf(writer, "%3d. %s\n", i+1, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
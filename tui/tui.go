@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"aoc2023/runner"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const HELP = "d <day> select  n/b next/back  i <input> choose input  1/2 run part  r run both  v verbose  o output  q quit"
+
+// Browser is a line based terminal UI for browsing the days, running
+// their parts and looking at the answers, timings and diagnostic output
+type Browser struct {
+	Days   []runner.Day
+	Input  io.Reader
+	Output io.Writer
+
+	selected   int
+	variants   map[int]string
+	results    map[int][]runner.Result
+	verbose    bool
+	lastOutput string
+	message    string
+}
+
+func (browser *Browser) Run() {
+	browser.variants = make(map[int]string)
+	browser.results = make(map[int][]runner.Result)
+	scanner := bufio.NewScanner(browser.Input)
+
+	for {
+		browser.Render()
+		if !scanner.Scan() {
+			return
+		}
+
+		command := strings.Fields(scanner.Text())
+		if len(command) == 0 {
+			continue
+		}
+
+		browser.message = ""
+		switch command[0] {
+		case "q":
+			return
+		case "d":
+			browser.selectDay(command[1:])
+		case "n":
+			browser.selected = (browser.selected + 1) % len(browser.Days)
+		case "b":
+			browser.selected = (browser.selected + len(browser.Days) - 1) % len(browser.Days)
+		case "i":
+			browser.selectVariant(command[1:])
+		case "1", "2":
+			part, _ := strconv.Atoi(command[0])
+			browser.runParts(scanner, part)
+		case "r":
+			browser.runParts(scanner, 1, 2)
+		case "v":
+			browser.verbose = !browser.verbose
+		case "o":
+			browser.showOutput(scanner)
+		default:
+			browser.message = "unknown command: " + command[0]
+		}
+	}
+}
+
+func (browser *Browser) Render() {
+	fmt.Fprint(browser.Output, "\033[H\033[2J")
+	fmt.Fprintln(browser.Output, "Advent of Code 2023")
+	fmt.Fprintln(browser.Output)
+	fmt.Fprintf(browser.Output, "  %-4s %-34s %-24s %s\n", "Day", "Title", "Part 1", "Part 2")
+
+	for i, day := range browser.Days {
+		marker := " "
+		if i == browser.selected {
+			marker = ">"
+		}
+
+		answers := []string{"-", "-"}
+		for _, result := range browser.results[day.Number] {
+			answers[result.Part-1] = result.String()
+		}
+
+		fmt.Fprintf(browser.Output, "%s %-4d %-34s %-24s %s\n",
+			marker, day.Number, day.Title, answers[0], answers[1])
+	}
+
+	day := browser.selectedDay()
+	fmt.Fprintln(browser.Output)
+	fmt.Fprintf(browser.Output, "%s, input: %s (available: %s), verbose: %t\n",
+		day, browser.variant(day), strings.Join(day.Variants(), ", "), browser.verbose)
+	if browser.message != "" {
+		fmt.Fprintln(browser.Output, browser.message)
+	}
+	fmt.Fprintln(browser.Output, HELP)
+	fmt.Fprint(browser.Output, "> ")
+}
+
+func (browser *Browser) selectedDay() runner.Day {
+	return browser.Days[browser.selected]
+}
+
+func (browser *Browser) variant(day runner.Day) string {
+	if variant, ok := browser.variants[day.Number]; ok {
+		return variant
+	}
+	return day.DefaultVariant()
+}
+
+func (browser *Browser) selectDay(args []string) {
+	if len(args) != 1 {
+		browser.message = "usage: d <day>"
+		return
+	}
+
+	number, err := strconv.Atoi(args[0])
+	if err != nil {
+		browser.message = "invalid day: " + args[0]
+		return
+	}
+
+	for i, day := range browser.Days {
+		if day.Number == number {
+			browser.selected = i
+			return
+		}
+	}
+	browser.message = fmt.Sprintf("day %d is not registered", number)
+}
+
+func (browser *Browser) selectVariant(args []string) {
+	day := browser.selectedDay()
+	if len(args) != 1 || !slices.Contains(day.Variants(), args[0]) {
+		browser.message = "usage: i <input>, available inputs: " + strings.Join(day.Variants(), ", ")
+		return
+	}
+
+	browser.variants[day.Number] = args[0]
+	browser.results[day.Number] = nil
+}
+
+// runParts runs the parts of the selected day, in verbose mode the output
+// of the solver is streamed to the terminal while it runs
+func (browser *Browser) runParts(scanner *bufio.Scanner, parts ...int) {
+	day := browser.selectedDay()
+	variant := browser.variant(day)
+
+	lines, err := runner.ReadInput(day.VariantPath(variant))
+	if err != nil {
+		browser.message = err.Error()
+		return
+	}
+
+	var output bytes.Buffer
+	var writer io.Writer = &output
+	if browser.verbose {
+		writer = io.MultiWriter(&output, browser.Output)
+		fmt.Fprintf(browser.Output, "\n%s, input: %s\n", day, variant)
+	}
+
+	for _, part := range parts {
+		result := day.Solve(part, lines, writer)
+		browser.storeResult(result)
+
+		if browser.verbose {
+			fmt.Fprintf(browser.Output, "[PART %d] %s\n", part, result)
+		}
+	}
+
+	browser.lastOutput = output.String()
+	if browser.verbose {
+		waitForEnter(browser.Output, scanner)
+	}
+}
+
+func (browser *Browser) storeResult(result runner.Result) {
+	results := browser.results[result.Day]
+	for i := range results {
+		if results[i].Part == result.Part {
+			results[i] = result
+			return
+		}
+	}
+	browser.results[result.Day] = append(results, result)
+}
+
+func (browser *Browser) showOutput(scanner *bufio.Scanner) {
+	fmt.Fprint(browser.Output, "\033[H\033[2J")
+	if browser.lastOutput == "" {
+		fmt.Fprintln(browser.Output, "(the last run printed nothing)")
+	} else {
+		fmt.Fprint(browser.Output, browser.lastOutput)
+	}
+	waitForEnter(browser.Output, scanner)
+}
+
+func waitForEnter(output io.Writer, scanner *bufio.Scanner) {
+	fmt.Fprint(output, "\npress enter to continue")
+	scanner.Scan()
+}
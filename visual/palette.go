@@ -0,0 +1,75 @@
+package visual
+
+import (
+	"fmt"
+	imagecolor "image/color"
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+)
+
+const (
+	COLOR_AUTO   = "auto"
+	COLOR_ALWAYS = "always"
+	COLOR_NEVER  = "never"
+)
+
+// Paint is the look of a style in the terminal and in the rendered pictures,
+// a style without a terminal colour is printed as it is
+type Paint struct {
+	Terminal *color.Color
+	RGBA     imagecolor.RGBA
+}
+
+// PALETTE is shared by all the renderers, so a style looks the same everywhere
+var PALETTE = map[Style]Paint{
+	PLAIN:    {nil, imagecolor.RGBA{0x30, 0x30, 0x3a, 0xff}},
+	WALL:     {color.New(color.FgHiBlack), imagecolor.RGBA{0x70, 0x70, 0x78, 0xff}},
+	ROCK:     {color.New(color.FgYellow, color.Bold), imagecolor.RGBA{0xe8, 0xc0, 0x30, 0xff}},
+	BEAM:     {color.New(color.FgHiYellow), imagecolor.RGBA{0xff, 0xf0, 0x60, 0xff}},
+	FRONTIER: {color.New(color.FgHiCyan, color.Bold), imagecolor.RGBA{0x40, 0xe0, 0xf0, 0xff}},
+	VISITED:  {color.New(color.FgBlue), imagecolor.RGBA{0x30, 0x50, 0xc0, 0xff}},
+	PATH:     {color.New(color.FgHiRed, color.Bold), imagecolor.RGBA{0xff, 0x40, 0x40, 0xff}},
+	LOOP:     {color.New(color.FgRed), imagecolor.RGBA{0xd0, 0x30, 0x30, 0xff}},
+	INSIDE:   {color.New(color.FgBlue), imagecolor.RGBA{0x40, 0x70, 0xe0, 0xff}},
+	HEAT_1:   {color.New(color.FgRed), imagecolor.RGBA{0x60, 0x20, 0x10, 0xff}},
+	HEAT_2:   {color.New(color.FgHiRed), imagecolor.RGBA{0xb0, 0x40, 0x10, 0xff}},
+	HEAT_3:   {color.New(color.FgYellow), imagecolor.RGBA{0xf0, 0x90, 0x20, 0xff}},
+	HEAT_4:   {color.New(color.FgHiYellow, color.Bold), imagecolor.RGBA{0xff, 0xf0, 0x80, 0xff}},
+}
+
+// SetColorMode turns the terminal colours on or off for all the renderers.
+// In the auto mode they are used only when stdout is a terminal and NO_COLOR is not set.
+func SetColorMode(mode string) error {
+	switch mode {
+	case COLOR_AUTO:
+		color.NoColor = os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" || !isTerminal(os.Stdout)
+	case COLOR_ALWAYS:
+		color.NoColor = false
+	case COLOR_NEVER:
+		color.NoColor = true
+	default:
+		return fmt.Errorf("invalid color mode %q, use %s, %s or %s", mode, COLOR_AUTO, COLOR_ALWAYS, COLOR_NEVER)
+	}
+	return nil
+}
+
+func ColorsEnabled() bool {
+	return !color.NoColor
+}
+
+// Fprint writes the text in the terminal colour of the style
+func Fprint(output io.Writer, style Style, text string) {
+	terminal := PALETTE[style].Terminal
+	if terminal == nil {
+		io.WriteString(output, text)
+		return
+	}
+	terminal.Fprint(output, text)
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,136 @@
+package visual
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const MIN_DELAY = 5 * time.Millisecond
+const CONTROLS = "[enter] pause/resume  [n] next  [b] back  [+] faster  [-] slower  [q] quit"
+
+// Player plays frames back in the terminal, controls are read line by line
+// from Input so they work without switching the terminal to raw mode
+type Player struct {
+	Frames []Frame
+	Delay  time.Duration
+	Input  io.Reader
+	Output io.Writer
+}
+
+func (player *Player) Play() {
+	if len(player.Frames) == 0 {
+		return
+	}
+
+	// the reader stops at the next line once the player is done
+	done := make(chan bool)
+	defer close(done)
+
+	var commands chan string
+	if player.Input != nil {
+		commands = make(chan string)
+		go readCommands(player.Input, commands, done)
+	}
+
+	// a ticker needs a positive interval
+	player.Delay = max(player.Delay, time.Nanosecond)
+
+	index, paused := 0, false
+	ticker := time.NewTicker(player.Delay)
+	defer ticker.Stop()
+
+	player.Render(index, paused)
+
+	for {
+		select {
+		case command, ok := <-commands:
+			if !ok {
+				// no more input, just play the rest of the frames
+				commands = nil
+				continue
+			}
+
+			switch command {
+			case "":
+				paused = !paused
+			case "n":
+				paused = true
+				if index < len(player.Frames)-1 {
+					index++
+				}
+			case "b":
+				paused = true
+				if index > 0 {
+					index--
+				}
+			case "+":
+				player.Delay = max(player.Delay/2, MIN_DELAY)
+				ticker.Reset(player.Delay)
+			case "-":
+				player.Delay *= 2
+				ticker.Reset(player.Delay)
+			case "q":
+				return
+			}
+			player.Render(index, paused)
+
+		case <-ticker.C:
+			if paused {
+				continue
+			}
+			if index == len(player.Frames)-1 {
+				return
+			}
+			index++
+			player.Render(index, paused)
+		}
+	}
+}
+
+func (player *Player) Render(index int, paused bool) {
+	frame := player.Frames[index]
+	status := "playing"
+	if paused {
+		status = "paused"
+	}
+
+	// move the cursor home and clear the screen before drawing the frame
+	fmt.Fprint(player.Output, "\033[H\033[2J")
+	fmt.Fprintf(player.Output, "%s  (frame %d/%d, %s, %v per frame)\n",
+		frame.Caption, index+1, len(player.Frames), status, player.Delay)
+	RenderFrame(player.Output, frame)
+	fmt.Fprintln(player.Output, CONTROLS)
+}
+
+// RenderFrame writes the frame with every run of equally styled cells coloured at once
+func RenderFrame(output io.Writer, frame Frame) {
+	for y, row := range frame.Cells {
+		for x := 0; x < len(row); {
+			style := frame.Styles[y][x]
+			end := x
+			for end < len(row) && frame.Styles[y][end] == style {
+				end++
+			}
+
+			Fprint(output, style, string(row[x:end]))
+			x = end
+		}
+		fmt.Fprintln(output)
+	}
+}
+
+func readCommands(input io.Reader, commands chan<- string, done <-chan bool) {
+	defer close(commands)
+
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		select {
+		case commands <- strings.TrimSpace(scanner.Text()):
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package visual
+
+type Style int
+
+const (
+	PLAIN Style = iota
+	WALL
+	ROCK
+	BEAM
+	FRONTIER
+	VISITED
+	PATH
+	LOOP
+	INSIDE
+	HEAT_1
+	HEAT_2
+	HEAT_3
+	HEAT_4
+)
+
+var HEAT_STYLES = []Style{PLAIN, HEAT_1, HEAT_2, HEAT_3, HEAT_4}
+
+// Frame is a snapshot of a grid with a style for every cell
+type Frame struct {
+	Cells   [][]byte
+	Styles  [][]Style
+	Caption string
+}
+
+// Recorder collects frames emitted by the solvers, a nil recorder ignores them
+type Recorder struct {
+	Frames []Frame
+}
+
+// Recorder used by the solvers, set by main when visualization is requested
+var Default *Recorder
+
+// NewFrame copies the cells, so the solver can keep mutating its grid,
+// and styles all of them as PLAIN
+func NewFrame(cells [][]byte, caption string) Frame {
+	frame := Frame{
+		Cells:   make([][]byte, len(cells)),
+		Styles:  make([][]Style, len(cells)),
+		Caption: caption,
+	}
+
+	for y, row := range cells {
+		frame.Cells[y] = make([]byte, len(row))
+		frame.Styles[y] = make([]Style, len(row))
+		copy(frame.Cells[y], row)
+	}
+
+	return frame
+}
+
+func (frame Frame) SetStyle(x, y int, style Style) {
+	frame.Styles[y][x] = style
+}
+
+func (recorder *Recorder) Enabled() bool {
+	return recorder != nil
+}
+
+func (recorder *Recorder) Record(frame Frame) {
+	if recorder == nil {
+		return
+	}
+	recorder.Frames = append(recorder.Frames, frame)
+}